@@ -3,6 +3,8 @@ package activate
 import (
 	"github.com/publiczny81/ml/errors"
 	"github.com/publiczny81/ml/functions"
+	"math"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -12,33 +14,143 @@ const (
 	separator = "@"
 )
 const (
-	Linear    = "linear"
-	Sigmoid   = "sigmoid"
-	Rectifier = "rectifier"
+	Linear     = "linear"
+	Sigmoid    = "sigmoid"
+	Rectifier  = "rectifier"
+	Softmax    = "softmax"
+	LogSoftmax = "logsoftmax"
+	Sparsemax  = "sparsemax"
 )
 
+const (
+	Tanh           = "tanh"
+	HardTanh       = "hardtanh"
+	Softplus       = "softplus"
+	Softsign       = "softsign"
+	ELU            = "elu"
+	SELU           = "selu"
+	GELU           = "gelu"
+	GELUTanh       = "gelutanh"
+	Swish          = "swish"
+	SiLU           = "silu"
+	Mish           = "mish"
+	HardSigmoid    = "hardsigmoid"
+	LeakyRectifier = "leakyrectifier"
+	Gaussian       = "gaussian"
+)
+
+const (
+	defaultELUAlpha            = 1.0
+	defaultSwishBeta           = 1.0
+	defaultLeakyRectifierSlope = 0.01
+)
+
+// Activate represents activation function of neurons. Scalar activations define Function and Derivative
+// which are applied to each neuron separately. Vector activations, like softmax, define Vector and VectorJVP
+// which are applied to the whole layer at once.
 type Activate struct {
 	Name       string
 	Function   func(float64) float64
 	Derivative func(float64) float64
+	// Vector computes outputs of the whole layer from its weighted sums
+	Vector func(sums, output []float64)
+	// VectorJVP computes the product of the Jacobian of Vector at given sums and outputs with partials.
+	// The result may share memory with partials
+	VectorJVP func(sums, output, partials, result []float64)
 }
 
-type factory func(...any) Activate
+// IsVector reports whether the activation is applied to the whole layer at once
+func (a Activate) IsVector() bool {
+	return a.Vector != nil
+}
 
-func Get(name string, params ...any) (a Activate, found bool) {
-	registerOnce.Do(func() {
-		register = map[string]factory{
-			Linear:    GetLinear,
-			Sigmoid:   GetSigmoid,
-			Rectifier: GetRectifier,
-		}
-	})
+// Apply computes outputs of the layer from its weighted sums
+func (a Activate) Apply(sums, output []float64) {
+	if a.IsVector() {
+		a.Vector(sums, output)
+		return
+	}
+	for i, sum := range sums {
+		output[i] = a.Function(sum)
+	}
+}
+
+// JVP computes the product of the Jacobian of the activation at given sums and outputs with partials.
+// The result may share memory with partials
+func (a Activate) JVP(sums, output, partials, result []float64) {
+	if a.IsVector() {
+		a.VectorJVP(sums, output, partials, result)
+		return
+	}
+	for i, sum := range sums {
+		result[i] = partials[i] * a.Derivative(sum)
+	}
+}
+
+// Factory creates activation from parameters given either explicitly or after separator in the name.
+// It returns error wrapping errors.InvalidParameterValueError when a parameter is missing or malformed
+type Factory func(...any) (Activate, error)
+
+var (
+	registerLock sync.RWMutex
+	register     = map[string]Factory{
+		Linear:     GetLinear,
+		Sigmoid:    GetSigmoid,
+		Rectifier:  GetRectifier,
+		Softmax:    GetSoftmax,
+		LogSoftmax: GetLogSoftmax,
+		Sparsemax:  GetSparsemax,
+
+		Tanh:           GetTanh,
+		HardTanh:       GetHardTanh,
+		Softplus:       GetSoftplus,
+		Softsign:       GetSoftsign,
+		ELU:            GetELU,
+		SELU:           GetSELU,
+		GELU:           GetGELU,
+		GELUTanh:       GetGELUTanh,
+		Swish:          GetSwish,
+		SiLU:           GetSiLU,
+		Mish:           GetMish,
+		HardSigmoid:    GetHardSigmoid,
+		LeakyRectifier: GetLeakyRectifier,
+		Gaussian:       GetGaussian,
+	}
+)
 
+// Register adds custom activation factory under given name, so it can be used by networks and codecs.
+// The name must not contain the parameter separator and must not be registered yet
+func Register(name string, f Factory) error {
+	if name == "" || strings.Contains(name, separator) {
+		return errors.WithMessagef(errors.InvalidParameterValueError, "activate.Register: name=%q", name)
+	}
+	if f == nil {
+		return errors.WithMessagef(errors.InvalidParameterError, "activate.Register: factory of %s is nil", name)
+	}
+	registerLock.Lock()
+	defer registerLock.Unlock()
+
+	if _, found := register[name]; found {
+		return errors.WithMessagef(errors.DuplicateNameError, "activate.Register: %s", name)
+	}
+	register[name] = f
+	return nil
+}
+
+// Get returns activation registered under the name. Parameters are given either explicitly or after separator
+// in the name, e.g. "rectifier@0.1". It returns error wrapping errors.UnknownNameError if the name is not registered
+// or errors.InvalidParameterValueError if parameters are missing or malformed
+func Get(name string, params ...any) (a Activate, err error) {
 	var (
 		names = strings.Split(name, separator)
-		f     factory
+		f     Factory
+		found bool
 	)
-	if f, found = register[names[0]]; !found {
+	registerLock.RLock()
+	f, found = register[names[0]]
+	registerLock.RUnlock()
+	if !found {
+		err = errors.WithMessagef(errors.UnknownNameError, "activate.Get: name=%s", name)
 		return
 	}
 
@@ -46,16 +158,10 @@ func Get(name string, params ...any) (a Activate, found bool) {
 		params = append(params, sub)
 	}
 
-	a = f(params...)
-	return
+	return f(params...)
 }
 
-var (
-	registerOnce sync.Once
-	register     = map[string]factory{}
-)
-
-func GetSigmoid(_ ...any) (a Activate) {
+func GetSigmoid(_ ...any) (a Activate, err error) {
 	a.Name = Sigmoid
 	a.Function = functions.Sigmoid
 	a.Derivative = func(value float64) float64 {
@@ -65,14 +171,14 @@ func GetSigmoid(_ ...any) (a Activate) {
 	return
 }
 
-func GetRectifier(params ...any) (a Activate) {
+func GetRectifier(params ...any) (a Activate, err error) {
 	if len(params) == 0 {
-		panic(errors.WithStack(errors.New("activate.GetRectifier: missing parameter")))
+		err = errors.WithMessage(errors.InvalidParameterValueError, "activate.GetRectifier: missing parameter")
 		return
 	}
-	var value, err = float64FromAny(params[0])
-	if err != nil {
-		panic(errors.WithStack(err))
+	var value float64
+	if value, err = float64FromAny(params[0]); err != nil {
+		err = errors.WithMessage(err, "activate.GetRectifier")
 		return
 	}
 	a.Name = "rectifier@" + strconv.FormatFloat(value, 'f', -1, 64)
@@ -81,27 +187,17 @@ func GetRectifier(params ...any) (a Activate) {
 	return
 }
 
-func GetLinear(params ...any) (f Activate) {
-	var (
-		a, b float64
-		err  error
-	)
-	switch len(params) {
-	case 0:
-		a, b = 1.0, 0.0
-	case 1:
+func GetLinear(params ...any) (f Activate, err error) {
+	var a, b = 1.0, 0.0
+	if len(params) > 0 {
 		if a, err = float64FromAny(params[0]); err != nil {
-			panic(errors.WithStack(err))
-			return
-		}
-		b = 0.0
-	default:
-		if a, err = float64FromAny(params[0]); err != nil {
-			panic(errors.WithStack(err))
+			err = errors.WithMessage(err, "activate.GetLinear")
 			return
 		}
+	}
+	if len(params) > 1 {
 		if b, err = float64FromAny(params[1]); err != nil {
-			panic(errors.WithStack(err))
+			err = errors.WithMessage(err, "activate.GetLinear")
 			return
 		}
 	}
@@ -112,15 +208,235 @@ func GetLinear(params ...any) (f Activate) {
 	return
 }
 
+func GetTanh(_ ...any) (Activate, error) {
+	return scalar(Tanh, functions.Tanh, functions.DerivativeTanh), nil
+}
+
+func GetHardTanh(_ ...any) (Activate, error) {
+	return scalar(HardTanh, functions.HardTanh, functions.DerivativeHardTanh), nil
+}
+
+func GetSoftplus(_ ...any) (Activate, error) {
+	return scalar(Softplus, functions.Softplus, functions.DerivativeSoftplus), nil
+}
+
+func GetSoftsign(_ ...any) (Activate, error) {
+	return scalar(Softsign, functions.Softsign, functions.DerivativeSoftsign), nil
+}
+
+// GetELU returns exponential linear unit. The optional parameter is alpha which defaults to 1
+func GetELU(params ...any) (a Activate, err error) {
+	var alpha float64
+	if alpha, err = optionalFloat64(params, defaultELUAlpha); err != nil {
+		err = errors.WithMessage(err, "activate.GetELU")
+		return
+	}
+	a = scalar(parametricName(ELU, alpha), functions.ELU(alpha), functions.DerivativeELU(alpha))
+	return
+}
+
+func GetSELU(_ ...any) (Activate, error) {
+	return scalar(SELU, functions.SELU, functions.DerivativeSELU), nil
+}
+
+// GetGELU returns exact Gaussian error linear unit
+func GetGELU(_ ...any) (Activate, error) {
+	return scalar(GELU, functions.GELU, functions.DerivativeGELU), nil
+}
+
+// GetGELUTanh returns approximation of Gaussian error linear unit with hyperbolic tangent
+func GetGELUTanh(_ ...any) (Activate, error) {
+	return scalar(GELUTanh, functions.GELUTanh, functions.DerivativeGELUTanh), nil
+}
+
+// GetSwish returns swish x*sigmoid(beta*x). The optional parameter is beta which defaults to 1
+func GetSwish(params ...any) (a Activate, err error) {
+	var beta float64
+	if beta, err = optionalFloat64(params, defaultSwishBeta); err != nil {
+		err = errors.WithMessage(err, "activate.GetSwish")
+		return
+	}
+	a = scalar(parametricName(Swish, beta), functions.Swish(beta), functions.DerivativeSwish(beta))
+	return
+}
+
+// GetSiLU returns sigmoid linear unit which is swish with beta equal to 1
+func GetSiLU(_ ...any) (Activate, error) {
+	return scalar(SiLU, functions.Swish(1), functions.DerivativeSwish(1)), nil
+}
+
+func GetMish(_ ...any) (Activate, error) {
+	return scalar(Mish, functions.Mish, functions.DerivativeMish), nil
+}
+
+func GetHardSigmoid(_ ...any) (Activate, error) {
+	return scalar(HardSigmoid, functions.HardSigmoid, functions.DerivativeHardSigmoid), nil
+}
+
+// GetLeakyRectifier returns rectifier with small slope for negative values. The optional parameter is the slope
+// which defaults to 0.01
+func GetLeakyRectifier(params ...any) (a Activate, err error) {
+	var slope float64
+	if slope, err = optionalFloat64(params, defaultLeakyRectifierSlope); err != nil {
+		err = errors.WithMessage(err, "activate.GetLeakyRectifier")
+		return
+	}
+	a = scalar(parametricName(LeakyRectifier, slope), functions.ParametricRectifier(slope), functions.DerivativeParametricRectifier(slope))
+	return
+}
+
+func GetGaussian(_ ...any) (Activate, error) {
+	return scalar(Gaussian, functions.Gaussian, functions.DerivativeGaussian), nil
+}
+
+func scalar(name string, function, derivative func(float64) float64) (a Activate) {
+	a.Name = name
+	a.Function = function
+	a.Derivative = derivative
+	return
+}
+
+func parametricName(name string, values ...float64) string {
+	for _, value := range values {
+		name += separator + strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return name
+}
+
+func optionalFloat64(params []any, defaultValue float64) (float64, error) {
+	if len(params) == 0 {
+		return defaultValue, nil
+	}
+	return float64FromAny(params[0])
+}
+
+// GetSoftmax returns softmax activation which turns weighted sums of the layer into probabilities
+func GetSoftmax(_ ...any) (a Activate, err error) {
+	a.Name = Softmax
+	a.Vector = softmax
+	a.VectorJVP = func(_, output, partials, result []float64) {
+		var dot float64
+		for i, y := range output {
+			dot += y * partials[i]
+		}
+		for i, y := range output {
+			result[i] = y * (partials[i] - dot)
+		}
+	}
+	return
+}
+
+// GetLogSoftmax returns logarithm of softmax activation which is numerically stable for large weighted sums
+func GetLogSoftmax(_ ...any) (a Activate, err error) {
+	a.Name = LogSoftmax
+	a.Vector = func(sums, output []float64) {
+		var (
+			m   = slices.Max(sums)
+			sum float64
+		)
+		for _, x := range sums {
+			sum += math.Exp(x - m)
+		}
+		var lse = m + math.Log(sum)
+		for i, x := range sums {
+			output[i] = x - lse
+		}
+	}
+	a.VectorJVP = func(_, output, partials, result []float64) {
+		var total float64
+		for _, p := range partials {
+			total += p
+		}
+		for i, y := range output {
+			result[i] = partials[i] - math.Exp(y)*total
+		}
+	}
+	return
+}
+
+// GetSparsemax returns sparsemax activation which is the Euclidean projection of weighted sums onto
+// the probability simplex. Unlike softmax, it may return exact zeros
+func GetSparsemax(_ ...any) (a Activate, err error) {
+	a.Name = Sparsemax
+	a.Vector = func(sums, output []float64) {
+		var (
+			sorted = slices.Clone(sums)
+			cumsum float64
+			k      int
+			tau    float64
+		)
+		slices.Sort(sorted)
+		slices.Reverse(sorted)
+		for i, z := range sorted {
+			cumsum += z
+			if 1+float64(i+1)*z > cumsum {
+				k = i + 1
+				tau = (cumsum - 1) / float64(k)
+			}
+		}
+		for i, x := range sums {
+			output[i] = max(0, x-tau)
+		}
+	}
+	a.VectorJVP = func(_, output, partials, result []float64) {
+		var (
+			sum     float64
+			support int
+		)
+		for i, y := range output {
+			if y > 0 {
+				sum += partials[i]
+				support++
+			}
+		}
+		for i, y := range output {
+			if y > 0 {
+				result[i] = partials[i] - sum/float64(support)
+			} else {
+				result[i] = 0
+			}
+		}
+	}
+	return
+}
+
+func softmax(sums, output []float64) {
+	var (
+		m   = slices.Max(sums)
+		sum float64
+	)
+	for i, x := range sums {
+		output[i] = math.Exp(x - m)
+		sum += output[i]
+	}
+	for i := range output {
+		output[i] /= sum
+	}
+}
+
 func float64FromAny(value any) (float64, error) {
 	switch val := value.(type) {
 	case float64:
 		return val, nil
-	case int8, int16, int32, int64, int, float32:
-		return val.(float64), nil
+	case float32:
+		return float64(val), nil
+	case int:
+		return float64(val), nil
+	case int8:
+		return float64(val), nil
+	case int16:
+		return float64(val), nil
+	case int32:
+		return float64(val), nil
+	case int64:
+		return float64(val), nil
 	case string:
-		return strconv.ParseFloat(val, 64)
+		var f, err = strconv.ParseFloat(val, 64)
+		if err != nil {
+			return 0, errors.WithMessagef(errors.InvalidParameterValueError, "parameter %q is not a number", val)
+		}
+		return f, nil
 	default:
-		return 0, errors.New("activate.float64FromAny: invalid type")
+		return 0, errors.WithMessagef(errors.InvalidParameterValueError, "parameter %v of type %T is not a number", value, value)
 	}
 }
@@ -1,7 +1,11 @@
 package activate
 
 import (
+	"github.com/publiczny81/ml/errors"
 	"github.com/stretchr/testify/assert"
+	"math"
+	"strconv"
+	"sync"
 	"testing"
 )
 
@@ -10,53 +14,78 @@ func TestGet(t *testing.T) {
 		Name     string
 		Params   []any
 		Expected string
-		Found    bool
+		Error    error
 	}{
 		{
 			Name:     Sigmoid,
 			Expected: Sigmoid,
-			Found:    true,
 		},
 		{
 			Name:     Rectifier,
 			Params:   []any{0.1},
 			Expected: "rectifier@0.1",
-			Found:    true,
 		},
 		{
 			Name:     "rectifier@0.1",
 			Expected: "rectifier@0.1",
-			Found:    true,
 		},
 		{
 			Name:     Linear,
 			Expected: "linear@1@0",
-			Found:    true,
 		},
 		{
 			Name:     "linear@2@1",
 			Expected: "linear@2@1",
-			Found:    true,
 		},
 		{
 			Name:     "linear",
 			Params:   []any{2.0, 1.0},
 			Expected: "linear@2@1",
-			Found:    true,
 		},
 		{
 			Name:  "undefined",
-			Found: false,
+			Error: errors.UnknownNameError,
+		},
+		{
+			Name:  Rectifier,
+			Error: errors.InvalidParameterValueError,
+		},
+		{
+			Name:  "rectifier@abc",
+			Error: errors.InvalidParameterValueError,
+		},
+		{
+			Name:   Rectifier,
+			Params: []any{struct{}{}},
+			Error:  errors.InvalidParameterValueError,
+		},
+		{
+			Name:  "linear@2@x",
+			Error: errors.InvalidParameterValueError,
+		},
+		{
+			Name:  "elu@alpha",
+			Error: errors.InvalidParameterValueError,
+		},
+		{
+			Name:     Rectifier,
+			Params:   []any{1},
+			Expected: "rectifier@1",
+		},
+		{
+			Name:     Linear,
+			Params:   []any{int8(2), float32(0.5)},
+			Expected: "linear@2@0.5",
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
-			var a, found = Get(test.Name, test.Params...)
-			if !test.Found {
-				assert.False(t, found)
+			var a, err = Get(test.Name, test.Params...)
+			if test.Error != nil {
+				assert.ErrorIs(t, err, test.Error)
 				return
 			}
-			assert.True(t, found)
+			assert.NoError(t, err)
 			assert.Equal(t, test.Expected, a.Name)
 			assert.NotNil(t, a.Function)
 			assert.NotNil(t, a.Derivative)
@@ -65,7 +94,8 @@ func TestGet(t *testing.T) {
 }
 
 func TestGetSigmoid(t *testing.T) {
-	var a = GetSigmoid()
+	var a, err = GetSigmoid()
+	assert.NoError(t, err)
 	assert.Equal(t, Sigmoid, a.Name)
 	assert.NotNil(t, a.Function)
 	assert.NotNil(t, a.Derivative)
@@ -74,7 +104,8 @@ func TestGetSigmoid(t *testing.T) {
 }
 
 func TestGetRectifier(t *testing.T) {
-	var a = GetRectifier(0.1)
+	var a, err = GetRectifier(0.1)
+	assert.NoError(t, err)
 	assert.Equal(t, "rectifier@0.1", a.Name)
 	assert.NotNil(t, a.Function)
 	assert.NotNil(t, a.Derivative)
@@ -87,10 +118,220 @@ func TestGetRectifier(t *testing.T) {
 }
 
 func TestGetLinear(t *testing.T) {
-	var a = GetLinear(2.5, 1.0)
+	var a, err = GetLinear(2.5, 1.0)
+	assert.NoError(t, err)
 	assert.Equal(t, "linear@2.5@1", a.Name)
 	assert.NotNil(t, a.Function)
 	assert.NotNil(t, a.Derivative)
 	assert.Equal(t, 0.0, a.Function(-0.4))
 	assert.Equal(t, 2.5, a.Derivative(-0.4))
 }
+
+func TestVectorActivations(t *testing.T) {
+	var tests = []struct {
+		Name     string
+		Sums     []float64
+		Expected []float64
+	}{
+		{
+			Name:     Softmax,
+			Sums:     []float64{1, 2, 3},
+			Expected: []float64{0.09003057317038046, 0.24472847105479767, 0.6652409557748219},
+		},
+		{
+			Name:     LogSoftmax,
+			Sums:     []float64{1, 2, 3},
+			Expected: []float64{-2.40760596444438, -1.4076059644443801, -0.4076059644443803},
+		},
+		{
+			Name:     Sparsemax,
+			Sums:     []float64{0.1, 1.1, 0.2},
+			Expected: []float64{0, 0.95, 0.05},
+		},
+		{
+			Name:     Sparsemax,
+			Sums:     []float64{3, 0, 1},
+			Expected: []float64{1, 0, 0},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var a, err = Get(test.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, test.Name, a.Name)
+			assert.True(t, a.IsVector())
+
+			var output = make([]float64, len(test.Sums))
+			a.Apply(test.Sums, output)
+			assert.InDeltaSlice(t, test.Expected, output, 1e-12)
+		})
+	}
+}
+
+func TestVectorJVP(t *testing.T) {
+	const h = 1e-6
+	var (
+		sums     = []float64{0.3, -0.2, 0.5, 0.1}
+		partials = []float64{1, -2, 0.5, 3}
+	)
+	for _, name := range []string{Softmax, LogSoftmax, Sparsemax} {
+		t.Run(name, func(t *testing.T) {
+			var (
+				a, _     = Get(name)
+				output   = make([]float64, len(sums))
+				actual   = make([]float64, len(sums))
+				expected = make([]float64, len(sums))
+				shifted  = make([]float64, len(sums))
+			)
+			a.Apply(sums, output)
+			a.JVP(sums, output, partials, actual)
+
+			// the Jacobian is symmetric, so J*v is computed with finite differences along each sum
+			for j := range sums {
+				var x = append([]float64(nil), sums...)
+				x[j] += h
+				a.Apply(x, shifted)
+				for i := range sums {
+					expected[j] += partials[i] * (shifted[i] - output[i]) / h
+				}
+			}
+			assert.InDeltaSlice(t, expected, actual, 1e-5)
+		})
+	}
+}
+
+func TestScalarApplyAndJVP(t *testing.T) {
+	var (
+		a, err = GetLinear(2.0, 1.0)
+		output = make([]float64, 2)
+		result = make([]float64, 2)
+	)
+	assert.NoError(t, err)
+	assert.False(t, a.IsVector())
+	a.Apply([]float64{1, 2}, output)
+	assert.Equal(t, []float64{3, 5}, output)
+	a.JVP([]float64{1, 2}, output, []float64{1, -1}, result)
+	assert.Equal(t, []float64{2, -2}, result)
+}
+
+func TestScalarActivations(t *testing.T) {
+	const h = 1e-6
+	var tests = []struct {
+		Name     string
+		Expected string
+		Values   map[float64]float64
+	}{
+		{Name: Tanh, Expected: Tanh, Values: map[float64]float64{0: 0, 1: 0.7615941559557649}},
+		{Name: HardTanh, Expected: HardTanh, Values: map[float64]float64{-2: -1, 0.5: 0.5, 2: 1}},
+		{Name: Softplus, Expected: Softplus, Values: map[float64]float64{0: math.Ln2, 800: 800}},
+		{Name: Softsign, Expected: Softsign, Values: map[float64]float64{1: 0.5, -3: -0.75}},
+		{Name: ELU, Expected: "elu@1", Values: map[float64]float64{2: 2, -1: -0.6321205588285577}},
+		{Name: "elu@0.5", Expected: "elu@0.5", Values: map[float64]float64{-1: -0.31606027941427883}},
+		{Name: SELU, Expected: SELU, Values: map[float64]float64{1: 1.0507009873554805}},
+		{Name: GELU, Expected: GELU, Values: map[float64]float64{0: 0, 1: 0.8413447460685429}},
+		{Name: GELUTanh, Expected: GELUTanh, Values: map[float64]float64{0: 0, 1: 0.8411919906082768}},
+		{Name: Swish, Expected: "swish@1", Values: map[float64]float64{0: 0, 1: 0.7310585786300049}},
+		{Name: "swish@2", Expected: "swish@2", Values: map[float64]float64{1: 0.8807970779778823}},
+		{Name: SiLU, Expected: SiLU, Values: map[float64]float64{1: 0.7310585786300049}},
+		{Name: Mish, Expected: Mish, Values: map[float64]float64{0: 0, 1: 0.8650983882673103}},
+		{Name: HardSigmoid, Expected: HardSigmoid, Values: map[float64]float64{-4: 0, 0: 0.5, 4: 1}},
+		{Name: LeakyRectifier, Expected: "leakyrectifier@0.01", Values: map[float64]float64{-1: -0.01, 2: 2}},
+		{Name: Gaussian, Expected: Gaussian, Values: map[float64]float64{0: 1, 1: math.Exp(-1)}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var a, err = Get(test.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, a.Name)
+			assert.False(t, a.IsVector())
+
+			for x, expected := range test.Values {
+				assert.InDelta(t, expected, a.Function(x), 1e-12)
+			}
+			for _, x := range []float64{-2.5, -0.7, -0.2, 0.3, 0.8, 2.5} {
+				var expected = (a.Function(x+h) - a.Function(x-h)) / (2 * h)
+				assert.InDelta(t, expected, a.Derivative(x), 1e-6, "x=%v", x)
+			}
+
+			restored, err := Get(a.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, a.Name, restored.Name)
+		})
+	}
+}
+
+// unregister removes the activation registered by the test, so tests can be run repeatedly
+func unregister(t *testing.T, name string) {
+	t.Cleanup(func() {
+		registerLock.Lock()
+		defer registerLock.Unlock()
+		delete(register, name)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	var custom = func(_ ...any) (Activate, error) {
+		return GetLinear(3.0, 0.0)
+	}
+	unregister(t, "custom_activation")
+	var tests = []struct {
+		Name    string
+		Factory Factory
+		Error   error
+	}{
+		{
+			Name:    "custom_activation",
+			Factory: custom,
+		},
+		{
+			Name:    "custom_activation",
+			Factory: custom,
+			Error:   errors.DuplicateNameError,
+		},
+		{
+			Name:    Sigmoid,
+			Factory: custom,
+			Error:   errors.DuplicateNameError,
+		},
+		{
+			Name:    "custom@1",
+			Factory: custom,
+			Error:   errors.InvalidParameterValueError,
+		},
+		{
+			Name:  "custom_nil",
+			Error: errors.InvalidParameterError,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var err = Register(test.Name, test.Factory)
+			if test.Error != nil {
+				assert.ErrorIs(t, err, test.Error)
+				return
+			}
+			assert.NoError(t, err)
+			a, err := Get(test.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, 6.0, a.Function(2))
+		})
+	}
+}
+
+func TestRegisterConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := range 10 {
+		unregister(t, "concurrent_"+strconv.Itoa(i))
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, Register("concurrent_"+strconv.Itoa(i), GetSigmoid))
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := Get(Sigmoid)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
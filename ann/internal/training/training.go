@@ -0,0 +1,112 @@
+// Package training holds the random number generator and checkpointing shared by trainers of networks
+package training
+
+import (
+	"context"
+	"encoding"
+	"github.com/publiczny81/ml/ann/initializers"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/ports"
+	"github.com/publiczny81/ml/sampling"
+	"github.com/publiczny81/ml/utils"
+	"math/rand/v2"
+)
+
+// Stateful is implemented by schedules and random number generators whose state can be saved and restored,
+// e.g. learning.ReduceOnPlateau or utils.Random
+type Stateful interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// randomized is implemented by samplers which draw random numbers, e.g. sampling.Sampler
+type randomized interface {
+	SetRand(sampling.Rand)
+}
+
+// splitter is implemented by generators which create independent child streams, e.g. utils.Random
+type splitter interface {
+	Split() *utils.Random
+}
+
+// Base holds settings of a trainer of networks N which saves checkpoints C
+type Base[N, C any] struct {
+	// Rand is the random number generator of training. The global source is used when it is nil
+	Rand types.Rand
+	// Every is the number of epochs between checkpoints
+	Every int
+	// Save persists the checkpoint. Checkpoints are not taken when it is nil
+	Save func(ctx context.Context, network N, checkpoint C) error
+}
+
+func (b *Base[N, C]) generator() types.Rand {
+	if b.Rand == nil {
+		return utils.Rand
+	}
+	return b.Rand
+}
+
+// Initializer draws initial weights from the generator, so seeded runs are reproducible
+func (b *Base[N, C]) Initializer() *initializers.Initializer {
+	return initializers.NewNormal(rand.New(b.generator()))
+}
+
+// Seed gives the sampler its own child stream of the generator when the sampler draws random numbers,
+// so the goroutine of the sampler does not share the generator. Samplers keep their own generators
+// when Rand is not set
+func (b *Base[N, C]) Seed(sampler any) {
+	var s, ok = sampler.(randomized)
+	if !ok || b.Rand == nil {
+		return
+	}
+	if r, ok := b.Rand.(splitter); ok {
+		s.SetRand(r.Split())
+		return
+	}
+	s.SetRand(b.Rand)
+}
+
+// Marshal returns states of the schedule and the generator when they are Stateful
+func (b *Base[N, C]) Marshal(schedule any) (scheduleState, randState []byte, err error) {
+	if s, ok := schedule.(Stateful); ok {
+		if scheduleState, err = s.MarshalBinary(); err != nil {
+			return
+		}
+	}
+	if r, ok := b.Rand.(Stateful); ok {
+		randState, err = r.MarshalBinary()
+	}
+	return
+}
+
+// Unmarshal restores states of the schedule and the generator returned by Marshal. Missing states are skipped
+func (b *Base[N, C]) Unmarshal(schedule any, scheduleState, randState []byte) (err error) {
+	if s, ok := schedule.(Stateful); ok && len(scheduleState) > 0 {
+		if err = s.UnmarshalBinary(scheduleState); err != nil {
+			return errors.WithMessage(err, "checkpoint.Schedule")
+		}
+	}
+	if r, ok := b.Rand.(Stateful); ok && len(randState) > 0 {
+		if err = r.UnmarshalBinary(randState); err != nil {
+			return errors.WithMessage(err, "checkpoint.Rand")
+		}
+	}
+	return
+}
+
+// Due reports whether the checkpoint is saved after the epoch
+func (b *Base[N, C]) Due(epoch int, last bool) bool {
+	return b.Save != nil && (last || epoch%b.Every == 0)
+}
+
+// Checkpoint saves the checkpoint taken after the epoch when it is due
+func (b *Base[N, C]) Checkpoint(ctx context.Context, network N, epoch int, last bool, take func(epoch int) (C, error)) error {
+	if !b.Due(epoch, last) {
+		return nil
+	}
+	var c, err = take(epoch)
+	if err != nil {
+		return err
+	}
+	return b.Save(ctx, network, c)
+}
@@ -2,7 +2,6 @@ package lstm
 
 // write long short term memory network
 type Lstm struct {
-
 }
 
 // create new lstm network
@@ -1,99 +1,464 @@
 package mlp
 
-//
-//import (
-//	"github.com/pkg/errors"
-//	"github.com/publiczny81/ml/ann/neuron"
-//	"github.com/publiczny81/ml/calculus/types"
-//	errors2 "github.com/publiczny81/ml/errors"
-//	"github.com/publiczny81/ml/utils/slices"
-//)
-//
-//type sampler[T types.Float] interface {
-//	Next() [][]T
-//	HasNext() bool
-//	Reset()
-//}
-//
-//type learningRate[T types.Float] interface {
-//	LearningRate(epoch int) T
-//}
-//
-//type lossFunction[T types.Float] func([]T, []T) ([]T, T)
-//
-//type BackPropagationTrainer[T types.Float] struct {
-//	sampler[T]
-//	learningRate[T]
-//	lossFunction[T]
-//	dActivateFunc func(T) T
-//	epochs        int
-//	stopCondition func(T) bool
-//	errors        [][]T
-//}
-//
-//type NeuronWithError[T types.Float] struct {
-//	*neuron.Neuron[T]
-//	Error T
-//}
-//
-//func (t *BackPropagationTrainer[T]) Train(net *Network[T]) (err T, epoch int, trained bool) {
-//	if net == nil {
-//		panic(errors.WithMessage(errors2.InvalidParameterError, "BackPropagationTrainer.Train: net is nil"))
-//	}
-//	t.init(net)
-//	for epoch = range t.epochs {
-//		err = t.train(net, epoch)
-//		if trained = t.stopCondition(err); trained {
-//			return
-//		}
-//	}
-//	return
-//}
-//
-//func (t *BackPropagationTrainer[T]) init(net *Network[T]) {
-//	t.errors = make([][]T, 0, len(net.Neurons))
-//	slices.Reverse(net.Neurons, func(l Layer[T]) bool {
-//		t.errors = append(t.errors, make([]T, len(l)))
-//		return true
-//	})
-//}
-//
-//func (t *BackPropagationTrainer[T]) train(net *Network[T], epoch int) (err T) {
-//	var lr = t.learningRate.LearningRate(epoch)
-//
-//	t.sampler.Reset()
-//
-//	for t.sampler.HasNext() {
-//		var (
-//			sample         = t.sampler.Next()
-//			partials, loss = t.forwardStep(net, sample)
-//		)
-//		t.backwardStep(net, partials)
-//		t.nudgeNeurons(net, lr)
-//		err += loss
-//	}
-//	return
-//}
-//
-//func (t *BackPropagationTrainer[T]) forwardStep(net *Network[T], sample [][]T) (partials []T, loss T) {
-//	partials, loss = t.lossFunction(sample[1], net.Process(sample[0]))
-//	return
-//}
-//
-//func (t *BackPropagationTrainer[T]) backwardStep(net *Network[T], partials []T) {
-//	t.errors[0] = partials
-//	//slice.Reverse(net.Neurons, func(l Layer[T]) bool {
-//	//	slice.Reverse(l, func(n *NeuronWithError[T]) bool {
-//	//		t.errors[0] = slice.Apply(t.errors[0], func(i int, e T) T {
-//	//			return n.Error * n.ActivationFunc.Derivative(n.Inputs[i])
-//	//		})
-//	//		return true
-//	//	})
-//	//	return true
-//	//})
-//	panic("implement me")
-//}
-//
-//func (t *BackPropagationTrainer[T]) nudgeNeurons(net *Network[T], lr T) {
-//	panic("implement me")
-//}
+import (
+	"context"
+	"github.com/publiczny81/ml/ann/internal/training"
+	"github.com/publiczny81/ml/calculus/vector"
+	"github.com/publiczny81/ml/calculus/vector/operations"
+	"github.com/publiczny81/ml/callbacks"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/losses"
+	"github.com/publiczny81/ml/optimizers"
+	"github.com/publiczny81/ml/sampling"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// sampler provides training samples. Each sample is a pair of vectors where the first one is the input
+// and the second one is the target of the network, e.g. sampling.NewPairSource converts examples into such pairs
+type sampler interface {
+	Samples(ctx context.Context) <-chan sampling.Sample[[][]float64]
+}
+
+type learningRateSchedule interface {
+	LearningRate(epoch int) float64
+}
+
+// observer is implemented by schedules which react to the loss observed after each epoch,
+// e.g. learning.ReduceOnPlateau
+type observer interface {
+	Observe(epoch int, loss float64)
+}
+
+type initializer interface {
+	Initialize(s []float64)
+}
+
+type optimizer interface {
+	Step(parameters, gradients []float64, rate float64)
+}
+
+// Loss calculates partials and value of the loss between actual (target) and predicted values.
+// Partials point in the direction which decreases the loss, e.g. losses.MeanSquareError[float64]
+type Loss func(actual, predicted []float64) (partials []float64, value float64)
+
+// StopCondition decides whether training should stop given the mean loss of the last epoch.
+// The loss is evaluated on validation samples when they are set
+type StopCondition func(loss float64) bool
+
+type Trainer struct {
+	initializer
+	sampler
+	learningRateSchedule
+	optimizer
+	loss          Loss
+	stopCondition StopCondition
+	batchSize     int
+	validation    sampler
+	callbacks     callbacks.List
+	base          training.Base[*Network, Checkpoint]
+}
+
+type TrainerOption func(*Trainer)
+
+func WithInitializer(i initializer) TrainerOption {
+	return func(t *Trainer) {
+		t.initializer = i
+	}
+}
+
+// WithOptimizer sets the optimizer which nudges weights with gradients. The default is optimizers.NewSGD()
+func WithOptimizer(o optimizer) TrainerOption {
+	return func(t *Trainer) {
+		t.optimizer = o
+	}
+}
+
+// WithStopCondition sets the condition which stops training before all epochs are done
+func WithStopCondition(condition StopCondition) TrainerOption {
+	return func(t *Trainer) {
+		t.stopCondition = condition
+	}
+}
+
+// WithBatchSize sets the number of samples whose gradients are averaged before the weights are updated.
+// Samples of a batch are evaluated concurrently. The default is 1 which updates weights after each sample
+func WithBatchSize(size int) TrainerOption {
+	return func(t *Trainer) {
+		t.batchSize = max(1, size)
+	}
+}
+
+// WithValidation sets samples which are evaluated after each epoch. Their mean loss is passed to the stop condition
+// and to the schedule observing losses instead of the mean loss of training samples
+func WithValidation(validation sampler) TrainerOption {
+	return func(t *Trainer) {
+		t.validation = validation
+	}
+}
+
+// WithCallbacks sets callbacks notified about progress of training
+func WithCallbacks(cb ...callbacks.Callback) TrainerOption {
+	return func(t *Trainer) {
+		t.callbacks = append(t.callbacks, cb...)
+	}
+}
+
+func NewTrainer(sampler sampler, schedule learningRateSchedule, loss Loss, opts ...TrainerOption) (t *Trainer) {
+	t = &Trainer{
+		sampler:              sampler,
+		learningRateSchedule: schedule,
+		optimizer:            optimizers.NewSGD(),
+		loss:                 loss,
+		stopCondition: func(float64) bool {
+			return false
+		},
+		batchSize: 1,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.initializer == nil {
+		t.initializer = t.base.Initializer()
+	}
+	return
+}
+
+// Train initializes weights of the network and trains it with backpropagation for given number of epochs.
+// The network must be initialized with Network.Init before training
+func (t *Trainer) Train(ctx context.Context, network *Network, epochs int) (err error) {
+	if network == nil || len(network.Layers) == 0 {
+		return errors.WithMessage(errors.InvalidParameterError, "Trainer.Train: network is not initialized")
+	}
+	t.Initialize(network.Options.Weights)
+
+	return t.run(ctx, network, epochs, 1)
+}
+
+// newPasses creates buffers for each goroutine evaluating samples of a batch
+func (t *Trainer) newPasses(network *Network) (passes []*pass) {
+	for range min(runtime.NumCPU()*2-1, t.batchSize) {
+		passes = append(passes, newPass(network))
+	}
+	return
+}
+
+func (t *Trainer) train(ctx context.Context, network *Network, passes []*pass, p *progress, epochs, epoch int) (err error) {
+	if epochs < epoch {
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	p.epoch, p.batch, p.rate, p.loss = epoch, 0, t.learningRateSchedule.LearningRate(epoch), 0
+	if err = t.callbacks.OnEpochStart(ctx, p.event(network)); err != nil {
+		return
+	}
+	var loss float64
+	if loss, err = t.trainEpoch(ctx, network, passes, p); err != nil {
+		return
+	}
+	if t.validation != nil {
+		if loss, err = t.validate(ctx, network); err != nil {
+			return
+		}
+	}
+	if o, ok := t.learningRateSchedule.(observer); ok {
+		o.Observe(epoch, loss)
+	}
+	if err = t.base.Checkpoint(ctx, network, epoch, epoch == epochs, t.Checkpoint); err != nil {
+		return
+	}
+	p.batch, p.loss = 0, loss
+	if err = t.callbacks.OnEpochEnd(ctx, p.event(network)); err != nil {
+		return
+	}
+	if t.stopCondition(loss) {
+		return
+	}
+	return t.train(ctx, network, passes, p, epochs, epoch+1)
+}
+
+// validate returns weighted mean loss of validation samples
+func (t *Trainer) validate(ctx context.Context, network *Network) (loss float64, err error) {
+	t.base.Seed(t.validation)
+	var (
+		weights float64
+		output  []float64
+		samples = t.validation.Samples(ctx)
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		case sample, ok := <-samples:
+			if !ok {
+				loss = mean(loss, weights)
+				return
+			}
+			if sample.Error != nil {
+				err = sample.Error
+				return
+			}
+			if len(sample.Value) != 2 {
+				err = errors.WithMessagef(errors.InvalidParameterValueError, "len(sample)=%d", len(sample.Value))
+				return
+			}
+			if output, err = network.Predict(ctx, sample.Value[0], output); err != nil {
+				return
+			}
+			if len(sample.Value[1]) != len(output) {
+				err = errors.WithMessagef(errors.InvalidParameterValueError, "len(target)=%d", len(sample.Value[1]))
+				return
+			}
+			var _, value = t.loss(sample.Value[1], output)
+			loss += sample.EffectiveWeight() * value
+			weights += sample.EffectiveWeight()
+		}
+	}
+}
+
+// trainEpoch updates weights of the network after each batch and returns weighted mean loss of the epoch
+func (t *Trainer) trainEpoch(ctx context.Context, network *Network, passes []*pass, p *progress) (loss float64, err error) {
+	t.base.Seed(t.sampler)
+	var (
+		weights float64
+		batch   = make([]sampling.Sample[[][]float64], 0, t.batchSize)
+		samples = t.sampler.Samples(ctx)
+	)
+	var flush = func() (err error) {
+		var value, total float64
+		if value, err = t.trainBatch(ctx, network, passes, batch, p.rate); err != nil {
+			return
+		}
+		for _, sample := range batch {
+			total += sample.EffectiveWeight()
+		}
+		loss += value
+		weights += total
+		p.batch++
+		p.loss = mean(value, total)
+		batch = batch[:0]
+		return t.callbacks.OnBatchEnd(ctx, p.event(network))
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		case sample, ok := <-samples:
+			if !ok {
+				if len(batch) > 0 {
+					if err = flush(); err != nil {
+						return
+					}
+				}
+				loss = mean(loss, weights)
+				return
+			}
+			if sample.Error != nil {
+				err = sample.Error
+				return
+			}
+			if batch = append(batch, sample); len(batch) < t.batchSize {
+				continue
+			}
+			if err = flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// mean divides the sum of weighted losses by the sum of weights. It is zero when samples weigh nothing
+func mean(loss, weights float64) float64 {
+	if weights == 0 {
+		return 0
+	}
+	return loss / weights
+}
+
+// trainBatch evaluates samples of the batch concurrently, reduces their gradients and lets the optimizer
+// nudge weights with the mean gradient. It returns the sum of losses of the batch. The result does not depend
+// on scheduling of goroutines
+func (t *Trainer) trainBatch(ctx context.Context, network *Network, passes []*pass, batch []sampling.Sample[[][]float64], rate float64) (loss float64, err error) {
+	var (
+		wg      sync.WaitGroup
+		threads = min(len(passes), len(batch))
+		values  = make([]float64, threads)
+		errs    = make([]error, threads)
+	)
+
+	// samples are assigned to passes statically, so gradients are summed in the same order in each run
+	for i := range threads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := i; j < len(batch) && errs[i] == nil && ctx.Err() == nil; j += threads {
+				var value float64
+				value, errs[i] = passes[i].train(network, t.loss, batch[j])
+				values[i] += value
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	for i := range threads {
+		if errs[i] != nil {
+			err = errs[i]
+			return
+		}
+		loss += values[i]
+		if i > 0 {
+			passes[0].reduce(passes[i])
+		}
+	}
+	var gradients = passes[0].gradients
+	vector.Wrap(gradients).Apply(operations.Multiply(1 / float64(len(batch))))
+	t.optimizer.Step(network.Options.Weights, gradients, rate)
+	clear(gradients)
+	return
+}
+
+// progress tracks the state of training reported to callbacks
+type progress struct {
+	start time.Time
+	epoch int
+	batch int
+	rate  float64
+	loss  float64
+}
+
+func (p *progress) event(network *Network) callbacks.Event {
+	return callbacks.Event{
+		Epoch:   p.epoch,
+		Batch:   p.batch,
+		Rate:    p.rate,
+		Loss:    p.loss,
+		Elapsed: time.Since(p.start),
+		Weights: network.Options.Weights,
+	}
+}
+
+// pass holds buffers of a single forward and backward pass through the network
+type pass struct {
+	// inputs of each layer extended with bias followed by the output of the last layer
+	inputs [][]float64
+	// weighted sums of each layer
+	sums [][]float64
+	// errors of each layer
+	deltas [][]float64
+	// gradients ordered in the same way as the weights of the network
+	gradients []float64
+}
+
+func newPass(network *Network) (p *pass) {
+	p = &pass{
+		gradients: make([]float64, len(network.Options.Weights)),
+	}
+	var previous = network.Options.Input
+	for _, l := range network.Options.Layers {
+		var input = make([]float64, previous+1)
+		input[previous] = 1.0
+		p.inputs = append(p.inputs, input)
+		p.sums = append(p.sums, make([]float64, l.Neurons))
+		p.deltas = append(p.deltas, make([]float64, l.Neurons))
+		previous = l.Neurons
+	}
+	p.inputs = append(p.inputs, make([]float64, previous))
+	return
+}
+
+func (p *pass) output() []float64 {
+	return p.inputs[len(p.inputs)-1]
+}
+
+// train evaluates the sample and accumulates its gradients scaled by the weight of the sample.
+// Value of the sample is a pair of input and target vectors
+func (p *pass) train(network *Network, loss Loss, s sampling.Sample[[][]float64]) (value float64, err error) {
+	var sample = s.Value
+	if len(sample) != 2 {
+		err = errors.WithMessagef(errors.InvalidParameterValueError, "len(sample)=%d", len(sample))
+		return
+	}
+	if len(sample[0]) != network.Input {
+		err = errors.WithMessagef(errors.InvalidParameterValueError, "len(input)=%d", len(sample[0]))
+		return
+	}
+	if len(sample[1]) != len(p.output()) {
+		err = errors.WithMessagef(errors.InvalidParameterValueError, "len(target)=%d", len(sample[1]))
+		return
+	}
+	var partials []float64
+
+	p.forward(network, sample[0])
+	partials, value = loss(sample[1], p.output())
+	if weight := s.EffectiveWeight(); weight != 1 {
+		losses.Scale(partials, weight)
+		value *= weight
+	}
+	p.backward(network, partials)
+	return
+}
+
+// forward propagates input through the network keeping weighted sums of all layers
+func (p *pass) forward(network *Network, input []float64) {
+	copy(p.inputs[0], input)
+	for i := range network.Layers {
+		var (
+			l    = &network.Layers[i]
+			in   = p.inputs[i]
+			out  = p.inputs[i+1]
+			size = len(in)
+		)
+		for j := range p.sums[i] {
+			p.sums[i][j] = vector.DotProduct(in, l.Weights[j*size:(j+1)*size])
+		}
+		l.Activation.Apply(p.sums[i], out[:len(p.sums[i])])
+	}
+}
+
+// backward propagates partials of the loss from the last layer to the first one and accumulates gradients
+func (p *pass) backward(network *Network, partials []float64) {
+	var end = len(p.gradients)
+	copy(p.deltas[len(p.deltas)-1], partials)
+	for i := len(network.Layers) - 1; i >= 0; i-- {
+		var (
+			l     = &network.Layers[i]
+			in    = p.inputs[i]
+			size  = len(in)
+			start = end - len(l.Weights)
+		)
+		l.Activation.JVP(p.sums[i], p.inputs[i+1][:len(p.sums[i])], p.deltas[i], p.deltas[i])
+		for j, delta := range p.deltas[i] {
+			var gradients = p.gradients[start+j*size : start+(j+1)*size]
+			for k, x := range in {
+				gradients[k] += delta * x
+			}
+		}
+		if i > 0 {
+			// propagate errors to the previous layer skipping the bias
+			for k := range p.deltas[i-1] {
+				var value float64
+				for j, delta := range p.deltas[i] {
+					value += l.Weights[j*size+k] * delta
+				}
+				p.deltas[i-1][k] = value
+			}
+		}
+		end = start
+	}
+}
+
+// reduce adds gradients accumulated by other pass and resets them
+func (p *pass) reduce(other *pass) {
+	for i, g := range other.gradients {
+		p.gradients[i] += g
+	}
+	clear(other.gradients)
+}
@@ -1,53 +1,495 @@
 package mlp
 
-//
-//import (
-//	"github.com/publiczny81/ml/ann/neuron"
-//	"github.com/publiczny81/ml/functions"
-//	"github.com/publiczny81/ml/learning"
-//	"github.com/publiczny81/ml/losses"
-//	"github.com/publiczny81/ml/sampling"
-//	"github.com/stretchr/testify/suite"
-//	"math/rand/v2"
-//	"testing"
-//)
-//
-//type BackPropagationTrainerSuite struct {
-//	suite.Suite
-//}
-//
-//func TestBackPropagationTrainer(t *testing.T) {
-//	suite.Run(t, new(BackPropagationTrainerSuite))
-//}
-//
-//type randStub struct {
-//}
-//
-//func (s *randStub) Float() float64 {
-//	return 2*rand.Float64() - 1
-//}
-//
-//func (s *BackPropagationTrainerSuite) TestTrain() {
-//	var (
-//		net, err = New[float64]([]int{2, 2, 1},
-//			WithActivateFunc(neuron.NewActivateFunc(functions.Sigmoid)),
-//			WithRand(new(randStub)))
-//		source = sampling.NewSliceSource([][][]float64{
-//			{{0, 0}, {0}},
-//			{{1, 0}, {1}},
-//			{{0, 1}, {1}},
-//			{{1, 1}, {0}},
-//		})
-//		epochs  = 10
-//		trainer = BackPropagationTrainer[float64]{
-//			sampler: sampling.New(source, func() sampling.Strategy[[][]float64] {
-//				return sampling.NewSystematicalStrategyFromSource(source)
-//			}),
-//			learningRate: learning.LinearRateSchedule(epochs),
-//			epochs:       epochs,
-//			lossFunction: losses.MeanSquareError[float64],
-//		}
-//	)
-//	s.NoError(err)
-//	trainer.Train(net)
-//}
+import (
+	"context"
+	"fmt"
+	"github.com/publiczny81/ml/activate"
+	"github.com/publiczny81/ml/callbacks"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/learning"
+	"github.com/publiczny81/ml/losses"
+	"github.com/publiczny81/ml/optimizers"
+	"github.com/publiczny81/ml/sampling"
+	"github.com/publiczny81/ml/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"slices"
+	"testing"
+)
+
+type BackPropagationTrainerSuite struct {
+	suite.Suite
+}
+
+func TestBackPropagationTrainer(t *testing.T) {
+	suite.Run(t, new(BackPropagationTrainerSuite))
+}
+
+func (s *BackPropagationTrainerSuite) newInitializer() *mockInitializer {
+	var initializerMock = new(mockInitializer)
+	initializerMock.On("Initialize", mock.AnythingOfType("[]float64")).Run(func(args mock.Arguments) {
+		values := []float64{0.3, -0.5, 0.7, -0.2, 0.6, -0.7, 0.4, 0.3, -0.1, 0.5, -0.6, 0.2, 0.1}
+		slice := args.Get(0).([]float64)
+
+		for i := range slice {
+			slice[i] = values[i%len(values)]
+		}
+	})
+	return initializerMock
+}
+
+func (s *BackPropagationTrainerSuite) meanLoss(network *Network, samples [][][]float64) (loss float64) {
+	var p = newPass(network)
+	for _, sample := range samples {
+		p.forward(network, sample[0])
+		_, value := losses.MeanSquareError(sample[1], p.output())
+		loss += value
+	}
+	return loss / float64(len(samples))
+}
+
+func (s *BackPropagationTrainerSuite) TestTrain() {
+	var (
+		samples = [][][]float64{
+			{{0, 0}, {0}},
+			{{1, 0}, {1}},
+			{{0, 1}, {1}},
+			{{1, 1}, {0}},
+		}
+		source   = sampling.NewSliceSource(samples)
+		sampler  = sampling.New(source, new(sampling.SystematicalStrategy[[][]float64]))
+		epochs   = 2000
+		trainer  = NewTrainer(sampler, learning.ConstantRate(0.5), losses.MeanSquareError[float64], WithInitializer(s.newInitializer()))
+		net, err = New(2, AddLayer(3, activate.Sigmoid), AddLayer(1, activate.Sigmoid))
+	)
+	s.NoError(err)
+	s.NoError(net.Init())
+
+	err = trainer.Train(context.TODO(), net, epochs)
+	s.NoError(err)
+	s.Less(s.meanLoss(net, samples), 0.05)
+
+	trainer = NewTrainer(sampler, learning.ConstantRate(2), losses.MeanSquareError[float64],
+		WithInitializer(s.newInitializer()),
+		WithBatchSize(4))
+	err = trainer.Train(context.TODO(), net, epochs)
+	s.NoError(err)
+	s.Less(s.meanLoss(net, samples), 0.05)
+
+	trainer = NewTrainer(sampler, learning.ConstantRate(0.05), losses.MeanSquareError[float64],
+		WithInitializer(s.newInitializer()),
+		WithOptimizer(optimizers.NewAdam(0.9, 0.999, 1e-8)),
+		WithBatchSize(2))
+	err = trainer.Train(context.TODO(), net, epochs)
+	s.NoError(err)
+	s.Less(s.meanLoss(net, samples), 0.05)
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainWithSoftmax() {
+	var (
+		samples = [][][]float64{
+			{{1, 0}, {1, 0, 0}},
+			{{0, 1}, {0, 1, 0}},
+			{{1, 1}, {0, 0, 1}},
+		}
+		source   = sampling.NewSliceSource(samples)
+		sampler  = sampling.New(source, new(sampling.SystematicalStrategy[[][]float64]))
+		trainer  = NewTrainer(sampler, learning.ConstantRate(0.5), losses.MeanSquareError[float64], WithInitializer(s.newInitializer()))
+		net, err = New(2, AddLayer(4, activate.Sigmoid), AddLayer(3, activate.Softmax))
+	)
+	s.NoError(err)
+	s.NoError(net.Init())
+
+	s.NoError(trainer.Train(context.TODO(), net, 2000))
+	s.Less(s.meanLoss(net, samples), 0.01)
+	for _, sample := range samples {
+		output, err := net.Predict(context.TODO(), sample[0], nil)
+		s.NoError(err)
+		s.InDelta(1.0, output[0]+output[1]+output[2], 1e-9)
+	}
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainWithSoftmaxCrossEntropy() {
+	var (
+		samples = [][][]float64{
+			{{1, 0}, {1, 0, 0}},
+			{{0, 1}, {0, 1, 0}},
+			{{1, 1}, {0, 0, 1}},
+		}
+		source   = sampling.NewSliceSource(samples)
+		sampler  = sampling.New(source, new(sampling.SystematicalStrategy[[][]float64]))
+		loss, _  = losses.Get(losses.SoftmaxCrossEntropy)
+		trainer  = NewTrainer(sampler, learning.ConstantRate(0.1), loss.Function, WithInitializer(s.newInitializer()))
+		net, err = New(2, AddLayer(4, activate.Tanh), AddLayer(3, activate.Linear))
+	)
+	s.NoError(err)
+	s.NoError(net.Init())
+
+	s.NoError(trainer.Train(context.TODO(), net, 500))
+	for i, sample := range samples {
+		output, err := net.Predict(context.TODO(), sample[0], nil)
+		s.NoError(err)
+		s.Equal(i, slices.Index(output, slices.Max(output)))
+	}
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainWithBatchSize() {
+	var tests = []struct {
+		Name      string
+		BatchSize int
+		Samples   [][][]float64
+		Expected  []float64
+	}{
+		{
+			Name:      "When batch size is 2 then weights are nudged with mean gradient of both samples",
+			BatchSize: 2,
+			Samples:   [][][]float64{{{1}, {1}}, {{2}, {0}}},
+			Expected:  []float64{0.35, -0.445},
+		},
+		{
+			Name:      "When batch size exceeds number of samples then the last batch is not dropped",
+			BatchSize: 5,
+			Samples:   [][][]float64{{{1}, {1}}, {{2}, {0}}},
+			Expected:  []float64{0.35, -0.445},
+		},
+		{
+			Name:      "When batch size is 1 then weights are nudged after each sample",
+			BatchSize: 1,
+			Samples:   [][][]float64{{{1}, {1}}},
+			Expected:  []float64{0.42, -0.38},
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.Name, func() {
+			var (
+				source  = sampling.NewSliceSource(test.Samples)
+				sampler = sampling.New(source, new(sampling.SystematicalStrategy[[][]float64]))
+				trainer = NewTrainer(sampler, learning.ConstantRate(0.1), losses.MeanSquareError[float64],
+					WithInitializer(s.newInitializer()),
+					WithBatchSize(test.BatchSize))
+				net, err = New(1, AddLayer(1, activate.Linear))
+			)
+			s.NoError(err)
+			s.NoError(net.Init())
+			s.NoError(trainer.Train(context.TODO(), net, 1))
+			s.InDeltaSlice(test.Expected, net.Options.Weights, 1e-9)
+		})
+	}
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainWithSampleWeights() {
+	var (
+		source, err = sampling.NewWeightedSource[[][]float64](sampling.NewSliceSource([][][]float64{{{1}, {1}}}), []float64{0.5})
+		sampler     = sampling.New[[][]float64](source, new(sampling.SystematicalStrategy[[][]float64]))
+		trainer     = NewTrainer(sampler, learning.ConstantRate(0.1), losses.MeanSquareError[float64],
+			WithInitializer(s.newInitializer()))
+		net, _ = New(1, AddLayer(1, activate.Linear))
+	)
+	s.NoError(err)
+	s.NoError(net.Init())
+	s.NoError(trainer.Train(context.TODO(), net, 1))
+	s.InDeltaSlice([]float64{0.36, -0.44}, net.Options.Weights, 1e-9)
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainWithZeroSampleWeight() {
+	var (
+		source, err = sampling.NewWeightedSource[[][]float64](sampling.NewSliceSource([][][]float64{{{1}, {1}}}), []float64{0})
+		sampler     = sampling.New[[][]float64](source, new(sampling.SystematicalStrategy[[][]float64]))
+		trainer     = NewTrainer(sampler, learning.ConstantRate(0.1), losses.MeanSquareError[float64],
+			WithInitializer(s.newInitializer()))
+		net, _ = New(1, AddLayer(1, activate.Linear))
+	)
+	s.NoError(err)
+	s.NoError(net.Init())
+	s.NoError(trainer.Train(context.TODO(), net, 1))
+	s.InDeltaSlice([]float64{0.3, -0.5}, net.Options.Weights, 1e-9)
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainReportsWeightedMeanLoss() {
+	var (
+		source, err = sampling.NewWeightedSource[[][]float64](sampling.NewSliceSource([][][]float64{{{1}, {1}}, {{0}, {0}}}), []float64{3, 1})
+		sampler     = sampling.New[[][]float64](source, new(sampling.SystematicalStrategy[[][]float64]))
+		loss        float64
+		trainer     = NewTrainer(sampler, learning.ConstantRate(0), losses.MeanSquareError[float64],
+			WithInitializer(s.newInitializer()),
+			WithStopCondition(func(value float64) bool {
+				loss = value
+				return true
+			}))
+		net, _ = New(1, AddLayer(1, activate.Linear))
+		_, a   = losses.MeanSquareError([]float64{1}, []float64{-0.2})
+		_, b   = losses.MeanSquareError([]float64{0}, []float64{-0.5})
+	)
+	s.NoError(err)
+	s.NoError(net.Init())
+	s.NoError(trainer.Train(context.TODO(), net, 1))
+	s.InDelta((3*a+b)/4, loss, 1e-9)
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainWithStopCondition() {
+	var (
+		source  = sampling.NewSliceSource([][][]float64{{{1}, {1}}})
+		sampler = sampling.New(source, new(sampling.SystematicalStrategy[[][]float64]))
+		epochs  int
+		trainer = NewTrainer(sampler, learning.ConstantRate(0.1), losses.MeanSquareError[float64],
+			WithInitializer(s.newInitializer()),
+			WithStopCondition(func(loss float64) bool {
+				epochs++
+				return epochs == 3
+			}))
+		net, err = New(1, AddLayer(1, activate.Linear))
+	)
+	s.NoError(err)
+	s.NoError(net.Init())
+
+	err = trainer.Train(context.TODO(), net, 10)
+	s.NoError(err)
+	s.Equal(3, epochs)
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainErrors() {
+	var tests = []struct {
+		Name    string
+		Context func() context.Context
+		Samples [][][]float64
+		Network func() *Network
+		Error   error
+	}{
+		{
+			Name:    "When network is not initialized then return error",
+			Context: context.TODO,
+			Samples: [][][]float64{{{1}, {1}}},
+			Network: func() (n *Network) {
+				n, _ = New(1, AddLayer(1, activate.Linear))
+				return
+			},
+			Error: errors.InvalidParameterError,
+		},
+		{
+			Name:    "When sample has invalid input then return error",
+			Context: context.TODO,
+			Samples: [][][]float64{{{1, 2}, {1}}},
+			Network: func() (n *Network) {
+				n, _ = New(1, AddLayer(1, activate.Linear))
+				_ = n.Init()
+				return
+			},
+			Error: errors.InvalidParameterValueError,
+		},
+		{
+			Name:    "When sample has invalid target then return error",
+			Context: context.TODO,
+			Samples: [][][]float64{{{1}, {1, 2}}},
+			Network: func() (n *Network) {
+				n, _ = New(1, AddLayer(1, activate.Linear))
+				_ = n.Init()
+				return
+			},
+			Error: errors.InvalidParameterValueError,
+		},
+		{
+			Name: "When context is cancelled then return error",
+			Context: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			Samples: [][][]float64{{{1}, {1}}},
+			Network: func() (n *Network) {
+				n, _ = New(1, AddLayer(1, activate.Linear))
+				_ = n.Init()
+				return
+			},
+			Error: context.Canceled,
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.Name, func() {
+			var (
+				source  = sampling.NewSliceSource(test.Samples)
+				sampler = sampling.New(source, new(sampling.SystematicalStrategy[[][]float64]))
+				trainer = NewTrainer(sampler, learning.ConstantRate(0.1), losses.MeanSquareError[float64], WithInitializer(s.newInitializer()))
+				err     = trainer.Train(test.Context(), test.Network(), 1)
+			)
+			s.ErrorIs(err, test.Error)
+		})
+	}
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainWithObservingSchedule() {
+	var tests = []struct {
+		Name       string
+		Validation sampler
+		Expected   []float64
+	}{
+		{
+			Name:     "When validation samples are not set then schedule observes training loss",
+			Expected: []float64{1.44, 0.9216},
+		},
+		{
+			Name:       "When validation samples are set then schedule observes validation loss",
+			Validation: sampling.New(sampling.NewSliceSource([][][]float64{{{2}, {0}}}), new(sampling.SystematicalStrategy[[][]float64])),
+			Expected:   []float64{0.2116, 0.559504},
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.Name, func() {
+			var (
+				schedule = &observingSchedule{ConstantRate: 0.1}
+				sampler  = sampling.New(sampling.NewSliceSource([][][]float64{{{1}, {1}}}), new(sampling.SystematicalStrategy[[][]float64]))
+				opts     = []TrainerOption{WithInitializer(s.newInitializer())}
+				net, err = New(1, AddLayer(1, activate.Linear))
+			)
+			if test.Validation != nil {
+				opts = append(opts, WithValidation(test.Validation))
+			}
+			s.NoError(err)
+			s.NoError(net.Init())
+			s.NoError(NewTrainer(sampler, schedule, losses.MeanSquareError[float64], opts...).Train(context.TODO(), net, 2))
+			s.Equal([]int{1, 2}, schedule.epochs)
+			s.InDeltaSlice(test.Expected, schedule.losses, 1e-9)
+		})
+	}
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainWithCallbacks() {
+	var (
+		recorder = &recordingCallback{}
+		sampler  = sampling.New(sampling.NewSliceSource([][][]float64{{{1}, {1}}, {{2}, {0}}}), new(sampling.SystematicalStrategy[[][]float64]))
+		trainer  = NewTrainer(sampler, learning.ConstantRate(0.1), losses.MeanSquareError[float64],
+			WithInitializer(s.newInitializer()),
+			WithCallbacks(recorder))
+		net, err = New(1, AddLayer(1, activate.Linear))
+	)
+	s.NoError(err)
+	s.NoError(net.Init())
+	s.NoError(trainer.Train(context.TODO(), net, 2))
+	s.Equal([]string{
+		"epoch start 1/0", "batch end 1/1", "batch end 1/2", "epoch end 1/0",
+		"epoch start 2/0", "batch end 2/1", "batch end 2/2", "epoch end 2/0",
+		"train end 2/0",
+	}, recorder.events)
+	s.Equal(0.1, recorder.last.Rate)
+	s.Equal(net.Options.Weights, recorder.last.Weights)
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainWithStoppingCallback() {
+	var (
+		recorder = &recordingCallback{stopAt: 2}
+		sampler  = sampling.New(sampling.NewSliceSource([][][]float64{{{1}, {1}}}), new(sampling.SystematicalStrategy[[][]float64]))
+		trainer  = NewTrainer(sampler, learning.ConstantRate(0.1), losses.MeanSquareError[float64],
+			WithInitializer(s.newInitializer()),
+			WithCallbacks(recorder))
+		net, err = New(1, AddLayer(1, activate.Linear))
+	)
+	s.NoError(err)
+	s.NoError(net.Init())
+	s.NoError(trainer.Train(context.TODO(), net, 10))
+	s.Equal("train end 2/0", recorder.events[len(recorder.events)-1])
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainIsReproducible() {
+	var train = func(seed uint64) []float64 {
+		var (
+			samples [][][]float64
+			r       = utils.NewPCG(seed)
+		)
+		for i := range 64 {
+			var x = float64(i) / 64
+			samples = append(samples, [][]float64{{x, 1 - x}, {x * x}})
+		}
+		var (
+			sampler = sampling.New(sampling.NewSliceSource(samples), new(sampling.SystematicalStrategy[[][]float64]))
+			trainer = NewTrainer(sampler, learning.ConstantRate(0.1), losses.MeanSquareError[float64],
+				WithRand(r),
+				WithBatchSize(16),
+				WithOptimizer(optimizers.NewAdam(0.9, 0.999, 1e-8)))
+			net, err = New(2, AddLayer(8, activate.Tanh), AddLayer(1, activate.Linear))
+		)
+		s.NoError(err)
+		s.NoError(net.Init())
+		s.NoError(trainer.Train(context.TODO(), net, 5))
+		return net.Options.Weights
+	}
+	var expected = train(42)
+	s.Equal(expected, train(42))
+	s.NotEqual(expected, train(43))
+}
+
+func (s *BackPropagationTrainerSuite) TestTrainWithSeededStrategyIsReproducible() {
+	var train = func(seed uint64) []float64 {
+		var samples [][][]float64
+		for i := range 16 {
+			var x = float64(i) / 16
+			samples = append(samples, [][]float64{{x, 1 - x}, {x * x}})
+		}
+		var (
+			sampler  = sampling.New(sampling.NewSliceSource(samples), sampling.NewShuffleStrategy[[][]float64](utils.NewPCG(seed)))
+			trainer  = NewTrainer(sampler, learning.ConstantRate(0.5), losses.MeanSquareError[float64], WithInitializer(s.newInitializer()))
+			net, err = New(2, AddLayer(3, activate.Sigmoid), AddLayer(1, activate.Sigmoid))
+		)
+		s.NoError(err)
+		s.NoError(net.Init())
+		s.NoError(trainer.Train(context.TODO(), net, 5))
+		return net.Options.Weights
+	}
+	var expected = train(42)
+	s.Equal(expected, train(42))
+	s.NotEqual(expected, train(43))
+}
+
+// recordingCallback records events and stops training at the end of given epoch
+type recordingCallback struct {
+	callbacks.Base
+	events []string
+	last   callbacks.Event
+	stopAt int
+}
+
+func (r *recordingCallback) record(name string, e callbacks.Event) {
+	r.events = append(r.events, fmt.Sprintf("%s %d/%d", name, e.Epoch, e.Batch))
+	r.last = e
+}
+
+func (r *recordingCallback) OnEpochStart(_ context.Context, e callbacks.Event) error {
+	r.record("epoch start", e)
+	return nil
+}
+
+func (r *recordingCallback) OnEpochEnd(_ context.Context, e callbacks.Event) error {
+	r.record("epoch end", e)
+	if e.Epoch == r.stopAt {
+		return errors.StopTrainingError
+	}
+	return nil
+}
+
+func (r *recordingCallback) OnBatchEnd(_ context.Context, e callbacks.Event) error {
+	r.record("batch end", e)
+	return nil
+}
+
+func (r *recordingCallback) OnTrainEnd(_ context.Context, e callbacks.Event) error {
+	r.record("train end", e)
+	return nil
+}
+
+// observingSchedule records losses passed by the trainer
+type observingSchedule struct {
+	learning.ConstantRate
+	epochs []int
+	losses []float64
+}
+
+func (o *observingSchedule) Observe(epoch int, loss float64) {
+	o.epochs = append(o.epochs, epoch)
+	o.losses = append(o.losses, loss)
+}
+
+type mockInitializer struct {
+	mock.Mock
+}
+
+func (m *mockInitializer) Initialize(s []float64) {
+	_ = m.Called(s)
+}
@@ -0,0 +1,93 @@
+package mlp
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/optimizers"
+	"github.com/publiczny81/ml/ports"
+	"time"
+)
+
+// statefulOptimizer is implemented by optimizers whose state can be saved and restored, e.g. optimizers.Optimizer
+type statefulOptimizer interface {
+	State() optimizers.State
+	SetState(optimizers.State) error
+}
+
+// Checkpoint is a snapshot of training taken at the end of an epoch which allows to resume training
+type Checkpoint struct {
+	// Epoch is the last finished epoch
+	Epoch int
+	// Schedule is the state of the learning rate schedule when it is stateful
+	Schedule []byte
+	// Rand is the state of the random number generator set with WithRand when it is stateful
+	Rand []byte
+	// Optimizer is the state of the optimizer when it is stateful
+	Optimizer *optimizers.State
+}
+
+// CheckpointSaver persists the checkpoint together with the network, e.g. codecs/mlp.SaveCheckpoint
+type CheckpointSaver func(ctx context.Context, network *Network, checkpoint Checkpoint) error
+
+// WithRand sets random number generator of training, e.g. utils.NewPCG(seed). Unless WithInitializer is used,
+// initial weights are drawn from it. Samplers drawing random numbers, e.g. with sampling.RandomStrategy, get
+// their own child stream of it each epoch. Runs with generators seeded in the same way produce the same weights
+func WithRand(r types.Rand) TrainerOption {
+	return func(t *Trainer) {
+		t.base.Rand = r
+	}
+}
+
+// WithCheckpoints saves checkpoint every given number of epochs and after the last epoch
+func WithCheckpoints(every int, save CheckpointSaver) TrainerOption {
+	return func(t *Trainer) {
+		t.base.Every = max(1, every)
+		t.base.Save = save
+	}
+}
+
+// Checkpoint takes snapshot of the state of the trainer after given epoch
+func (t *Trainer) Checkpoint(epoch int) (c Checkpoint, err error) {
+	c.Epoch = epoch
+	if c.Schedule, c.Rand, err = t.base.Marshal(t.learningRateSchedule); err != nil {
+		return
+	}
+	if o, ok := t.optimizer.(statefulOptimizer); ok {
+		var state = o.State()
+		c.Optimizer = &state
+	}
+	return
+}
+
+// Resume restores the state of the trainer from the checkpoint and continues training of the network
+// from the epoch following the checkpoint. Weights of the network are not initialized
+func (t *Trainer) Resume(ctx context.Context, network *Network, checkpoint Checkpoint, epochs int) (err error) {
+	if network == nil || len(network.Layers) == 0 {
+		return errors.WithMessage(errors.InvalidParameterError, "Trainer.Resume: network is not initialized")
+	}
+	if checkpoint.Epoch < 0 {
+		return errors.WithMessagef(errors.InvalidParameterValueError, "checkpoint.Epoch=%d", checkpoint.Epoch)
+	}
+	if err = t.base.Unmarshal(t.learningRateSchedule, checkpoint.Schedule, checkpoint.Rand); err != nil {
+		return
+	}
+	if o, ok := t.optimizer.(statefulOptimizer); ok && checkpoint.Optimizer != nil {
+		if err = o.SetState(*checkpoint.Optimizer); err != nil {
+			return errors.WithMessage(err, "checkpoint.Optimizer")
+		}
+	}
+	return t.run(ctx, network, epochs, checkpoint.Epoch+1)
+}
+
+// run trains the network from given epoch and notifies callbacks about the end of training
+func (t *Trainer) run(ctx context.Context, network *Network, epochs, epoch int) (err error) {
+	var p = &progress{start: time.Now(), epoch: epoch - 1}
+	if err = t.train(ctx, network, t.newPasses(network), p, epochs, epoch); errors.Is(err, errors.StopTrainingError) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+	p.batch = 0
+	return t.callbacks.OnTrainEnd(ctx, p.event(network))
+}
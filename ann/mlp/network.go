@@ -3,8 +3,8 @@ package mlp
 import (
 	"context"
 	"github.com/publiczny81/ml/activate"
-	"github.com/publiczny81/ml/calculus/vector"
 	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/utils/pool"
 	"runtime"
 	"sync"
 )
@@ -54,62 +54,14 @@ func WithWeights(weights []float64) Option {
 
 type layer struct {
 	Activation activate.Activate
-	Input      []float64
-	Output     []float64
 	Weights    []float64
 }
 
-func (l *layer) getThreads() int {
-	return min(runtime.NumCPU()*2-1, len(l.Weights)/len(l.Input))
-}
-
-func (l *layer) Activate(ctx context.Context) (err error) {
-	type Neuron struct {
-		Start int
-		End   int
-		Index int
-	}
-	var (
-		threads = l.getThreads()
-		wg      sync.WaitGroup
-		ch      = make(chan *Neuron, threads)
-	)
-	for range threads {
-		wg.Add(1)
-		go func() {
-			for n := range ch {
-				l.Output[n.Index] = l.Activation.Function(vector.DotProduct(l.Input, l.Weights[n.Start:n.End]))
-			}
-			wg.Done()
-		}()
-	}
-
-	go func() {
-		defer close(ch)
-		var (
-			start = 0
-			end   = len(l.Input)
-		)
-		for idx := range len(l.Output) {
-			select {
-			case <-ctx.Done():
-				err = ctx.Err()
-				return
-			case ch <- &Neuron{
-				Start: start,
-				End:   end,
-				Index: idx,
-			}:
-			}
-		}
-	}()
-	wg.Wait()
-	return
-}
-
 type Network struct {
 	Options
 	Layers []layer
+	// passes provides scratch buffers for concurrent predictions
+	passes *pool.Pool[*pass]
 }
 
 func New(input int, opts ...Option) (net *Network, err error) {
@@ -143,18 +95,83 @@ func (net *Network) Init(opts ...Option) (err error) {
 
 }
 
+// Activate returns output of the network for given input.
+//
+// Deprecated: use Predict which allows to reuse output buffer
 func (net *Network) Activate(ctx context.Context, input []float64) (output []float64, err error) {
+	return net.Predict(ctx, input, nil)
+}
+
+// Predict computes output of the network for given input and stores it in out if it has enough capacity.
+// Otherwise, a new slice is allocated. It is safe to call Predict concurrently.
+func (net *Network) Predict(ctx context.Context, input, out []float64) (output []float64, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
 	if len(input) != net.Options.Input {
 		err = errors.WithMessagef(errors.InvalidParameterValueError, "len(input)=%d)", len(input))
 		return
 	}
-	copy(net.Layers[0].Input, input)
-	for _, l := range net.Layers {
-		if err = l.Activate(ctx); err != nil {
+	if net.passes == nil {
+		err = errors.WithMessage(errors.InvalidParameterError, "network is not initialized")
+		return
+	}
+	var p = net.passes.Get()
+	defer net.passes.Put(p)
+
+	p.forward(net, input)
+	if output = out[:0]; cap(output) < len(p.output()) {
+		output = make([]float64, 0, len(p.output()))
+	}
+	output = append(output, p.output()...)
+	return
+}
+
+// PredictBatch computes outputs of the network for all inputs concurrently
+func (net *Network) PredictBatch(ctx context.Context, inputs [][]float64) (outputs [][]float64, err error) {
+	var (
+		wg      sync.WaitGroup
+		threads = min(runtime.NumCPU()*2-1, len(inputs))
+		ch      = make(chan int, threads)
+		errs    = make([]error, threads)
+	)
+	outputs = make([][]float64, len(inputs))
+
+	for i := range threads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range ch {
+				if errs[i] != nil {
+					continue
+				}
+				outputs[idx], errs[i] = net.Predict(ctx, inputs[idx], nil)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(ch)
+		for idx := range inputs {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- idx:
+			}
+		}
+	}()
+	wg.Wait()
+
+	if err = ctx.Err(); err != nil {
+		outputs = nil
+		return
+	}
+	for _, err = range errs {
+		if err != nil {
+			outputs = nil
 			return
 		}
 	}
-	copy(output, net.Layers[len(net.Layers)-1].Output)
 	return
 }
 
@@ -186,29 +203,24 @@ func (net *Network) init() (err error) {
 		previous   = options.Input + 1
 		start      = 0
 		end        = 0
-		input      = make([]float64, options.Input, options.Input+1)
-		output     []float64
 		activation activate.Activate
-		found      bool
 	)
 
 	for i := 0; i < len(options.Layers); i++ {
-		input = append(input, 1.0)
-		output = make([]float64, options.Layers[i].Neurons, options.Layers[i].Neurons+1)
 		end += previous * options.Layers[i].Neurons
 		previous = options.Layers[i].Neurons + 1
-		if activation, found = activate.Get(options.Layers[i].Activation); !found {
-			return errors.WithMessagef(errors.InvalidParameterValueError, "network.Layer[%d].Activation=")
+		if activation, err = activate.Get(options.Layers[i].Activation); err != nil {
+			return errors.WithMessagef(err, "network.Layer[%d].Activation=%s", i, options.Layers[i].Activation)
 		}
 
 		net.Layers = append(net.Layers, layer{
 			Activation: activation,
-			Input:      input,
-			Output:     output,
 			Weights:    options.Weights[start:end],
 		})
 		start = end
-		input = output
 	}
+	net.passes = pool.New(func() *pass {
+		return newPass(net)
+	})
 	return
 }
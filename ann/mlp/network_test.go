@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"github.com/publiczny81/ml/activate"
+	errors2 "github.com/publiczny81/ml/errors"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"math/rand"
+	"strconv"
 	"testing"
+	"time"
 )
 
 type OptionsSuite struct {
@@ -150,6 +153,20 @@ func (s *NetworkSuite) TestInit() {
 			Options:       []Option{WithWeights([]float64{1, 2, 3, 4})},
 			ExpectedError: errors.New("incompatible parameters values len(network.Weights)=4 and network.Layers=[{sigmoid 2}]: invalid parameter value"),
 		},
+		{
+			Name: "When init network with malformed activation then return error with layer index",
+			Factory: func() (*Network, error) {
+				return New(2, AddLayer(2, activate.Sigmoid), AddLayer(1, "rectifier@x"))
+			},
+			ExpectedError: errors.New(`network.Layer[1].Activation=rectifier@x: activate.GetRectifier: parameter "x" is not a number: invalid parameter value`),
+		},
+		{
+			Name: "When init network with activation missing parameter then return error with layer index",
+			Factory: func() (*Network, error) {
+				return New(2, AddLayer(2, activate.Rectifier))
+			},
+			ExpectedError: errors.New("network.Layer[0].Activation=rectifier: activate.GetRectifier: missing parameter: invalid parameter value"),
+		},
 		{
 			Name: "When init network without initial weights then return no error",
 			Factory: func() (*Network, error) {
@@ -167,13 +184,9 @@ func (s *NetworkSuite) TestInit() {
 				Layers: []layer{
 					{
 						Weights: []float64{0, 0, 0, 0, 0, 0, 0, 0, 0},
-						Input:   []float64{0, 0, 1.0},
-						Output:  []float64{0, 0, 0},
 					},
 					{
 						Weights: []float64{0, 0, 0, 0, 0, 0, 0, 0},
-						Input:   []float64{0, 0, 0, 1.0},
-						Output:  []float64{0, 0},
 					},
 				},
 			},
@@ -200,12 +213,6 @@ func (s *NetworkSuite) TestInit() {
 					if success = s.Equal(test.Expected.Layers[i].Weights, l.Weights); !success {
 						return
 					}
-					if success = s.Equal(test.Expected.Layers[i].Input, l.Input); !success {
-						return
-					}
-					if success = s.Equal(test.Expected.Layers[i].Output, l.Output); !success {
-						return
-					}
 				}
 				success = true
 				return
@@ -214,6 +221,25 @@ func (s *NetworkSuite) TestInit() {
 	}
 }
 
+func (s *NetworkSuite) TestInitWithCustomActivation() {
+	// the registry is global, so the name is unique in each run of the test
+	var name = "double_" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	s.NoError(activate.Register(name, func(_ ...any) (activate.Activate, error) {
+		return activate.GetLinear(2.0, 0.0)
+	}))
+
+	var n, err = New(1, AddLayer(1, name))
+	s.NoError(err)
+	s.NoError(n.Init(WithWeights([]float64{1, 1})))
+	output, err := n.Predict(context.TODO(), []float64{2}, nil)
+	s.NoError(err)
+	s.Equal([]float64{6}, output)
+
+	n, err = New(1, AddLayer(1, "unregistered"))
+	s.NoError(err)
+	s.ErrorIs(n.Init(), errors2.UnknownNameError)
+}
+
 func (s *NetworkSuite) TestActivate() {
 	var tests = []struct {
 		Name          string
@@ -238,14 +264,14 @@ func (s *NetworkSuite) TestActivate() {
 				return
 			},
 			Input:    []float64{1, 1},
-			Expected: []float64{0.24190243276979864, 0.24190243276979864},
+			Expected: []float64{0.9793206273440739, 0.9793206273440739},
 		},
 	}
 	for _, test := range tests {
 		s.Run(test.Name, func() {
 			var (
-				n, _   = test.Factory()
-				_, err = n.Activate(context.TODO(), test.Input)
+				n, _        = test.Factory()
+				actual, err = n.Activate(context.TODO(), test.Input)
 			)
 			if test.ExpectedError != nil {
 				s.Error(err)
@@ -253,11 +279,128 @@ func (s *NetworkSuite) TestActivate() {
 				return
 			}
 			s.NoError(err)
-			s.Equal(test.Expected, n.Layers[len(n.Layers)-1].Output)
+			s.Equal(test.Expected, actual)
 		})
 	}
 }
 
+func (s *NetworkSuite) TestPredict() {
+	var tests = []struct {
+		Name          string
+		Factory       func() (*Network, error)
+		Context       func() context.Context
+		Input         []float64
+		Output        []float64
+		Expected      []float64
+		ExpectedError error
+	}{
+		{
+			Name: "When network is not initialized then return error",
+			Factory: func() (*Network, error) {
+				return New(2, AddLayer(2, activate.Sigmoid))
+			},
+			Context:       context.TODO,
+			Input:         []float64{1, 1},
+			ExpectedError: errors.New("network is not initialized: invalid parameter"),
+		},
+		{
+			Name: "When context is cancelled then return error",
+			Factory: func() (n *Network, err error) {
+				n, _ = New(2, AddLayer(2, activate.Sigmoid))
+				err = n.Init()
+				return
+			},
+			Context: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			Input:         []float64{1, 1},
+			ExpectedError: context.Canceled,
+		},
+		{
+			Name: "When output buffer is nil then return new output",
+			Factory: func() (n *Network, err error) {
+				n, _ = New(2, AddLayer(2, activate.Linear))
+				err = n.Init(WithWeights([]float64{1, 2, 3, 4, 5, 6}))
+				return
+			},
+			Context:  context.TODO,
+			Input:    []float64{1, -1},
+			Expected: []float64{2, 5},
+		},
+		{
+			Name: "When output buffer is given then return output in the buffer",
+			Factory: func() (n *Network, err error) {
+				n, _ = New(2, AddLayer(2, activate.Linear))
+				err = n.Init(WithWeights([]float64{1, 2, 3, 4, 5, 6}))
+				return
+			},
+			Context:  context.TODO,
+			Input:    []float64{1, -1},
+			Output:   make([]float64, 0, 2),
+			Expected: []float64{2, 5},
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.Name, func() {
+			var n, err = test.Factory()
+			s.NoError(err)
+
+			actual, err := n.Predict(test.Context(), test.Input, test.Output)
+			if test.ExpectedError != nil {
+				s.Error(err)
+				s.ErrorContains(err, test.ExpectedError.Error())
+				return
+			}
+			s.NoError(err)
+			s.Equal(test.Expected, actual)
+			if test.Output != nil {
+				s.Same(&test.Output[:1][0], &actual[0])
+			}
+		})
+	}
+}
+
+func (s *NetworkSuite) TestPredictBatch() {
+	var (
+		n, _   = New(2, AddLayer(3, activate.Sigmoid), AddLayer(2, activate.Linear))
+		inputs [][]float64
+	)
+	s.NoError(n.Init(WithWeights([]float64{0.1, -0.2, 0.3, -0.4, 0.5, -0.6, 0.7, -0.8, 0.9, 1, -1, 0.5, 0.2, 0.3, -0.3, 0.1, 0.2})))
+	for i := range 100 {
+		inputs = append(inputs, []float64{float64(i) / 10, float64(100-i) / 10})
+	}
+
+	actual, err := n.PredictBatch(context.TODO(), inputs)
+	s.NoError(err)
+	s.Len(actual, len(inputs))
+	for i, input := range inputs {
+		expected, err := n.Predict(context.TODO(), input, nil)
+		s.NoError(err)
+		s.Equal(expected, actual[i])
+	}
+
+	_, err = n.PredictBatch(context.TODO(), [][]float64{{1, 2}, {1}})
+	s.ErrorContains(err, "len(input)=1")
+}
+
+func BenchmarkPredict(b *testing.B) {
+	var n, _ = New(100, AddLayer(600, activate.Sigmoid), AddLayer(3, activate.Sigmoid))
+	var input []float64
+	for i := 0; i < 100; i++ {
+		input = append(input, rand.Float64())
+	}
+	_ = n.Init()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var output = make([]float64, 3)
+		for pb.Next() {
+			_, _ = n.Predict(context.TODO(), input, output)
+		}
+	})
+}
+
 func BenchmarkActivate(b *testing.B) {
 	var size = 6 * 100
 	var n, _ = New(100, AddLayer(600, activate.Sigmoid), AddLayer(3, activate.Sigmoid))
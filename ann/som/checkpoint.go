@@ -0,0 +1,70 @@
+package som
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/ports"
+	"time"
+)
+
+// Checkpoint is a snapshot of training taken at the end of an epoch which allows to resume training
+type Checkpoint struct {
+	// Epoch is the last finished epoch
+	Epoch int
+	// Schedule is the state of the learning rate schedule when it is stateful
+	Schedule []byte
+	// Rand is the state of the random number generator set with WithRand when it is stateful
+	Rand []byte
+}
+
+// CheckpointSaver persists the checkpoint together with the network, e.g. codecs/som.SaveCheckpoint
+type CheckpointSaver func(ctx context.Context, network *Network, checkpoint Checkpoint) error
+
+// WithRand sets random number generator of training, e.g. utils.NewPCG(seed). Unless WithInitializer is used,
+// initial weights are drawn from it. Samplers drawing random numbers, e.g. with sampling.RandomStrategy, get
+// their own child stream of it each epoch. Runs with generators seeded in the same way produce the same weights
+func WithRand(r types.Rand) TrainerOption {
+	return func(t *Trainer) {
+		t.base.Rand = r
+	}
+}
+
+// WithCheckpoints saves checkpoint every given number of epochs and after the last epoch
+func WithCheckpoints(every int, save CheckpointSaver) TrainerOption {
+	return func(t *Trainer) {
+		t.base.Every = max(1, every)
+		t.base.Save = save
+	}
+}
+
+// Checkpoint takes snapshot of the state of the trainer after given epoch
+func (t *Trainer) Checkpoint(epoch int) (c Checkpoint, err error) {
+	c.Epoch = epoch
+	c.Schedule, c.Rand, err = t.base.Marshal(t.learningRateSchedule)
+	return
+}
+
+// Resume restores the state of the trainer from the checkpoint and continues training of the network
+// from the epoch following the checkpoint. Weights of the network are not initialized
+func (t *Trainer) Resume(ctx context.Context, network *Network, checkpoint Checkpoint, epochs int) (err error) {
+	if checkpoint.Epoch < 0 {
+		return errors.WithMessagef(errors.InvalidParameterValueError, "checkpoint.Epoch=%d", checkpoint.Epoch)
+	}
+	if err = t.base.Unmarshal(t.learningRateSchedule, checkpoint.Schedule, checkpoint.Rand); err != nil {
+		return
+	}
+	return t.run(ctx, network, epochs, checkpoint.Epoch+1)
+}
+
+// run trains the network from given epoch and notifies callbacks about the end of training
+func (t *Trainer) run(ctx context.Context, network *Network, epochs, epoch int) (err error) {
+	var p = &progress{start: time.Now(), epoch: epoch - 1}
+	if err = t.train(ctx, network, p, epochs, epoch); errors.Is(err, errors.StopTrainingError) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+	p.batch = 0
+	return t.callbacks.OnTrainEnd(ctx, p.event(network))
+}
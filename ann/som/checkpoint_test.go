@@ -0,0 +1,49 @@
+package som
+
+import (
+	"context"
+	"github.com/publiczny81/ml/ann/initializers"
+	"github.com/publiczny81/ml/ann/som/neighbor"
+	"github.com/publiczny81/ml/learning"
+	"github.com/publiczny81/ml/sampling"
+	"github.com/publiczny81/ml/utils"
+	"math/rand/v2"
+)
+
+func (s *TrainerSuite) TestTrainIsReproducible() {
+	var train = func(seed uint64) []float64 {
+		var (
+			r            = utils.NewPCG(seed)
+			source       = sampling.NewSliceSource([][]float64{{1, 0}, {0, 1}, {1, 1}, {0.5, 0.2}, {0.1, 0.9}})
+			sampler      = sampling.New[[]float64](source, sampling.NewRandomStrategy[[]float64](utils.Rand))
+			trainer      = NewTrainer(sampler, learning.ConstantRate(0.5), neighbor.Identity(), WithRand(r))
+			network, err = New(2, []int{4, 4})
+		)
+		s.NoError(err)
+		s.NoError(network.Init())
+		s.NoError(trainer.Train(context.TODO(), network, 20))
+		return network.Weights
+	}
+	var expected = train(42)
+	s.Equal(expected, train(42))
+	s.NotEqual(expected, train(43))
+}
+
+func (s *TrainerSuite) TestTrainWithSeededStrategyIsReproducible() {
+	var train = func(seed uint64) []float64 {
+		var (
+			source       = sampling.NewSliceSource([][]float64{{1, 0}, {0, 1}, {1, 1}, {0.5, 0.2}, {0.1, 0.9}})
+			sampler      = sampling.New[[]float64](source, sampling.NewRandomStrategy[[]float64](utils.NewPCG(seed)))
+			initializer  = initializers.NewUniform(rand.New(rand.NewPCG(1, 2)))
+			trainer      = NewTrainer(sampler, learning.ConstantRate(0.5), neighbor.Identity(), WithInitializer(initializer))
+			network, err = New(2, []int{4, 4})
+		)
+		s.NoError(err)
+		s.NoError(network.Init())
+		s.NoError(trainer.Train(context.TODO(), network, 20))
+		return network.Weights
+	}
+	var expected = train(42)
+	s.Equal(expected, train(42))
+	s.NotEqual(expected, train(43))
+}
@@ -134,6 +134,10 @@ func validateConfig(config *config) (err error) {
 		err = errors.WithMessagef(errors.InvalidParameterValueError, "shape=%v", config.Shape)
 		return
 	}
+	if _, found := metrics.Get(config.Metrics); !found {
+		err = errors.WithMessagef(errors.InvalidParameterValueError, "metrics=%s", config.Metrics)
+		return
+	}
 	// adjust shape to topology
 	switch config.Topology {
 	case TopologyLinear:
@@ -206,26 +210,33 @@ func (net *Network) resizeWeights() {
 }
 
 func (net *Network) BestMatchingUnit(input []float64) (bmu Point) {
+	bmu, _ = net.bestMatchingUnit(input)
+	return
+}
+
+// bestMatchingUnit returns the point of the neuron nearest to input and its distance to input
+func (net *Network) bestMatchingUnit(input []float64) (bmu Point, minDistance float64) {
 	type item struct {
-		Point
+		Index    int
 		Distance float64
 	}
 
 	var (
-		threads     = min(runtime.NumCPU()*2-1, len(net.Neurons))
-		tasks       = make(chan *Neuron, threads)
-		results     = make(chan *item, threads)
-		minDistance = math.MaxFloat64
-		wg          sync.WaitGroup
+		threads = min(runtime.NumCPU()*2-1, len(net.Neurons))
+		tasks   = make(chan int, threads)
+		results = make(chan *item, threads)
+		wg      sync.WaitGroup
+		best    = -1
 	)
+	minDistance = math.MaxFloat64
 
 	for range threads {
 		wg.Add(1)
 		go func() {
 			for task := range tasks {
 				results <- &item{
-					Point:    task.Point,
-					Distance: task.Activate(input),
+					Index:    task,
+					Distance: net.Neurons[task].Activate(input),
 				}
 			}
 			wg.Done()
@@ -233,21 +244,23 @@ func (net *Network) BestMatchingUnit(input []float64) (bmu Point) {
 	}
 
 	go func() {
-		for _, n := range net.Neurons {
-
-			tasks <- n
+		for i := range net.Neurons {
+			tasks <- i
 		}
 		close(tasks)
 		wg.Wait()
 		close(results)
 	}()
 
+	// ties are resolved in favour of the first neuron, so the result does not depend on scheduling of goroutines
 	for result := range results {
-		if result.Distance < minDistance {
+		if result.Distance < minDistance || (result.Distance == minDistance && result.Index < best) {
 			minDistance = result.Distance
-			bmu = result.Point
+			best = result.Index
 		}
 	}
-
+	if best >= 0 {
+		bmu = net.Neurons[best].Point
+	}
 	return
 }
@@ -5,7 +5,10 @@ import (
 	"github.com/publiczny81/ml/metrics"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"math"
+	"strconv"
 	"testing"
+	"time"
 )
 
 type NetworkSuite struct {
@@ -175,6 +178,23 @@ func (s *NetworkSuite) TestBestMatchingUnit() {
 	}
 }
 
+func (s *NetworkSuite) TestBestMatchingUnitWithCustomMetrics() {
+	// the registry is global, so the name is unique in each run of the test
+	var name = "som_chebyshev_" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	s.NoError(metrics.Register(name, metrics.Metrics{
+		Function: func(x, y []float64) (value float64) {
+			for i := range x {
+				value = max(value, math.Abs(x[i]-y[i]))
+			}
+			return
+		},
+	}))
+	var network, err = New(2, []int{3}, WithMetrics(name))
+	s.NoError(err)
+	s.NoError(network.Init(WithWeights([]float64{0, 0, 3, 1, 1, 3})))
+	s.Equal(Point{1}, network.BestMatchingUnit([]float64{2, 1}))
+}
+
 type randMock struct {
 	mock.Mock
 }
@@ -2,17 +2,15 @@ package som
 
 import (
 	"context"
-	"github.com/publiczny81/ml/ann/initializers"
+	"github.com/publiczny81/ml/ann/internal/training"
 	"github.com/publiczny81/ml/calculus/vector"
 	"github.com/publiczny81/ml/calculus/vector/operations"
+	"github.com/publiczny81/ml/callbacks"
+	"github.com/publiczny81/ml/errors"
 	"github.com/publiczny81/ml/sampling"
-	"github.com/publiczny81/ml/utils"
 	"runtime"
 	"sync"
-)
-
-var (
-	defaultInitializer = initializers.NewNormal(utils.Rand)
+	"time"
 )
 
 type sampler interface {
@@ -23,6 +21,12 @@ type learningRateSchedule interface {
 	LearningRate(epoch int) float64
 }
 
+// observer is implemented by schedules which react to the quantization error observed after each epoch,
+// e.g. learning.ReduceOnPlateau
+type observer interface {
+	Observe(epoch int, loss float64)
+}
+
 type neighborhood interface {
 	NeighborRate([]float64, []float64, int) float64
 }
@@ -36,6 +40,8 @@ type Trainer struct {
 	sampler
 	learningRateSchedule
 	neighborhood
+	callbacks callbacks.List
+	base      training.Base[*Network, Checkpoint]
 }
 
 type TrainerOption func(*Trainer)
@@ -46,9 +52,16 @@ func WithInitializer(i initializer) TrainerOption {
 	}
 }
 
+// WithCallbacks sets callbacks notified about progress of training. Each sample is reported as a batch
+// and the loss is the quantization error
+func WithCallbacks(cb ...callbacks.Callback) TrainerOption {
+	return func(t *Trainer) {
+		t.callbacks = append(t.callbacks, cb...)
+	}
+}
+
 func NewTrainer(sampler sampler, schedule learningRateSchedule, neighborhood neighborhood, opts ...TrainerOption) (t *Trainer) {
 	t = &Trainer{
-		initializer:          defaultInitializer,
 		sampler:              sampler,
 		learningRateSchedule: schedule,
 		neighborhood:         neighborhood,
@@ -56,26 +69,74 @@ func NewTrainer(sampler sampler, schedule learningRateSchedule, neighborhood nei
 	for _, opt := range opts {
 		opt(t)
 	}
+	if t.initializer == nil {
+		t.initializer = t.base.Initializer()
+	}
 	return
 }
 
 func (t *Trainer) Train(ctx context.Context, network *Network, epochs int) (err error) {
 	t.Initialize(network.Weights)
 
-	return t.train(ctx, network, epochs, 1)
+	return t.run(ctx, network, epochs, 1)
 }
 
-func (t *Trainer) train(ctx context.Context, network *Network, epochs, epoch int) (err error) {
+func (t *Trainer) train(ctx context.Context, network *Network, p *progress, epochs, epoch int) (err error) {
 	if epochs < epoch {
 		return
 	}
-	if err = t.trainSample(ctx, network, epochs, epoch, t.sampler.Samples(ctx)); err != nil {
+	p.epoch, p.batch, p.rate, p.loss, p.distance = epoch, 0, t.learningRateSchedule.LearningRate(epoch), 0, 0
+	if err = t.callbacks.OnEpochStart(ctx, p.event(network)); err != nil {
+		return
+	}
+	t.base.Seed(t.sampler)
+	if err = t.trainSample(ctx, network, p, epochs, epoch, t.sampler.Samples(ctx)); err != nil {
+		return
+	}
+	if o, ok := t.learningRateSchedule.(observer); ok {
+		o.Observe(epoch, p.Error())
+	}
+	if err = t.base.Checkpoint(ctx, network, epoch, epoch == epochs, t.Checkpoint); err != nil {
+		return
+	}
+	p.batch, p.loss = 0, p.Error()
+	if err = t.callbacks.OnEpochEnd(ctx, p.event(network)); err != nil {
 		return
 	}
-	return t.train(ctx, network, epochs, epoch+1)
+	return t.train(ctx, network, p, epochs, epoch+1)
+}
+
+// progress tracks the state of training reported to callbacks. Each sample is reported as a batch
+type progress struct {
+	start time.Time
+	epoch int
+	batch int
+	rate  float64
+	loss  float64
+	// distance is the sum of distances between samples of the epoch and their best matching units
+	distance float64
+}
+
+// Error returns quantization error of the epoch, i.e. mean distance between samples and their best matching units
+func (p *progress) Error() float64 {
+	if p.batch == 0 {
+		return 0
+	}
+	return p.distance / float64(p.batch)
 }
 
-func (t *Trainer) trainSample(ctx context.Context, network *Network, epochs, epoch int, samples <-chan sampling.Sample[[]float64]) (err error) {
+func (p *progress) event(network *Network) callbacks.Event {
+	return callbacks.Event{
+		Epoch:   p.epoch,
+		Batch:   p.batch,
+		Rate:    p.rate,
+		Loss:    p.loss,
+		Elapsed: time.Since(p.start),
+		Weights: network.Weights,
+	}
+}
+
+func (t *Trainer) trainSample(ctx context.Context, network *Network, p *progress, epochs, epoch int, samples <-chan sampling.Sample[[]float64]) (err error) {
 	select {
 	case <-ctx.Done():
 		err = ctx.Err()
@@ -89,16 +150,23 @@ func (t *Trainer) trainSample(ctx context.Context, network *Network, epochs, epo
 			return
 		}
 
-		bmu := network.BestMatchingUnit(sample.Value)
+		bmu, distance := network.bestMatchingUnit(sample.Value)
 
-		if err = t.update(ctx, network, epoch, sample.Value, bmu); err != nil {
+		if err = t.update(ctx, network, epoch, sample.Value, sample.EffectiveWeight(), bmu); err != nil {
+			return
+		}
+		p.batch++
+		p.distance += distance
+		p.loss = distance
+		if err = t.callbacks.OnBatchEnd(ctx, p.event(network)); err != nil {
 			return
 		}
-		return t.trainSample(ctx, network, epochs, epoch, samples)
+		return t.trainSample(ctx, network, p, epochs, epoch, samples)
 	}
 }
 
-func (t *Trainer) update(ctx context.Context, network *Network, epoch int, features []float64, bmu Point) (err error) {
+// update moves weights of neurons towards features. The weight of the sample scales the learning factor
+func (t *Trainer) update(ctx context.Context, network *Network, epoch int, features []float64, weight float64, bmu Point) (err error) {
 	var (
 		wg      sync.WaitGroup
 		threads = min(runtime.NumCPU()*2-1, len(network.Neurons))
@@ -122,7 +190,7 @@ func (t *Trainer) update(ctx context.Context, network *Network, epoch int, featu
 						return
 					}
 					count++
-					var factor = t.learningRateSchedule.LearningRate(epoch) * t.neighborhood.NeighborRate(bmu, n.Point, epoch)
+					var factor = weight * t.learningRateSchedule.LearningRate(epoch) * t.neighborhood.NeighborRate(bmu, n.Point, epoch)
 					if factor <= 0 {
 						continue
 					}
@@ -149,3 +217,95 @@ func (t *Trainer) update(ctx context.Context, network *Network, epoch int, featu
 	wg.Wait()
 	return
 }
+
+// TrainOnline trains the network in a single pass over the stream, e.g. unbounded sampling.GeneratorStream.
+// Every period samples are treated as an epoch: the schedule and the neighborhood advance, the observer
+// and callbacks are notified and checkpoints are saved. Weights of the network are not initialized, so
+// an already trained network keeps adapting. Training ends when the stream ends
+func (t *Trainer) TrainOnline(ctx context.Context, network *Network, stream sampler, period int) (err error) {
+	if period < 1 {
+		return errors.WithMessagef(errors.InvalidParameterValueError, "period=%d", period)
+	}
+	var (
+		p      = &progress{start: time.Now()}
+		cancel context.CancelFunc
+	)
+	ctx, cancel = context.WithCancel(ctx)
+	t.base.Seed(stream)
+	var samples = stream.Samples(ctx)
+	defer sampling.Drain(cancel, samples)
+	if err = t.online(ctx, network, p, period, samples); errors.Is(err, errors.StopTrainingError) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+	p.batch = 0
+	return t.callbacks.OnTrainEnd(ctx, p.event(network))
+}
+
+// online trains the network on consecutive periods of the stream until it ends. A period starts when its first
+// sample arrives, so no empty epoch is reported when the stream ends with a period
+func (t *Trainer) online(ctx context.Context, network *Network, p *progress, period int, samples <-chan sampling.Sample[[]float64]) (err error) {
+	var started bool
+	for {
+		var (
+			sample sampling.Sample[[]float64]
+			ok     bool
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case sample, ok = <-samples:
+		}
+		if !ok && started {
+			return t.endPeriod(ctx, network, p, true)
+		}
+		if !ok {
+			// the last period is saved even if it is not due
+			if p.epoch > 0 && !t.base.Due(p.epoch, false) {
+				return t.base.Checkpoint(ctx, network, p.epoch, true, t.Checkpoint)
+			}
+			return
+		}
+		if sample.Error != nil {
+			return sample.Error
+		}
+		if !started {
+			var epoch = p.epoch + 1
+			p.epoch, p.batch, p.rate, p.loss, p.distance = epoch, 0, t.learningRateSchedule.LearningRate(epoch), 0, 0
+			if err = t.callbacks.OnEpochStart(ctx, p.event(network)); err != nil {
+				return
+			}
+			started = true
+		}
+		var bmu, distance = network.bestMatchingUnit(sample.Value)
+		if err = t.update(ctx, network, p.epoch, sample.Value, sample.EffectiveWeight(), bmu); err != nil {
+			return
+		}
+		p.batch++
+		p.distance += distance
+		p.loss = distance
+		if err = t.callbacks.OnBatchEnd(ctx, p.event(network)); err != nil {
+			return
+		}
+		if p.batch == period {
+			if err = t.endPeriod(ctx, network, p, false); err != nil {
+				return
+			}
+			started = false
+		}
+	}
+}
+
+// endPeriod notifies the observer and callbacks about the end of the period and saves the checkpoint when it is due
+func (t *Trainer) endPeriod(ctx context.Context, network *Network, p *progress, last bool) (err error) {
+	if o, ok := t.learningRateSchedule.(observer); ok {
+		o.Observe(p.epoch, p.Error())
+	}
+	if err = t.base.Checkpoint(ctx, network, p.epoch, last, t.Checkpoint); err != nil {
+		return
+	}
+	p.batch, p.loss = 0, p.Error()
+	return t.callbacks.OnEpochEnd(ctx, p.event(network))
+}
@@ -1,13 +1,20 @@
 package som
 
 import (
+	"bytes"
 	"context"
 	"github.com/publiczny81/ml/ann/som/neighbor"
 	"github.com/publiczny81/ml/calculus/utils"
+	"github.com/publiczny81/ml/callbacks"
+	"github.com/publiczny81/ml/errors"
 	"github.com/publiczny81/ml/learning"
 	"github.com/publiczny81/ml/sampling"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"io"
+	"math"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -65,6 +72,216 @@ func (s *TrainerSuite) TestTrain() {
 	})
 }
 
+func (s *TrainerSuite) TestTrainWithSampleWeights() {
+	var initializerMock = new(mockInitializer)
+	initializerMock.On("Initialize", mock.AnythingOfType("[]float64")).Run(func(args mock.Arguments) {
+		copy(args.Get(0).([]float64), []float64{0.3, 0.5, 0.7, 0.2, 0.6, 0.7, 0.4, 0.3})
+	})
+	var (
+		source, err = sampling.NewWeightedSource[[]float64](sampling.NewSliceSource([][]float64{{1, 0, 1, 0}}), []float64{0.5})
+		sampler     = sampling.New[[]float64](source, new(sampling.SystematicalStrategy[[]float64]))
+		trainer     = NewTrainer(sampler, learning.ConstantRate(0.6), neighbor.Identity(), WithInitializer(initializerMock))
+		network, _  = New(4, []int{2})
+	)
+	s.NoError(err)
+	s.NoError(network.Init())
+	s.NoError(trainer.Train(context.TODO(), network, 1))
+	s.InDeltaSlice([]float64{0.51, 0.35, 0.79, 0.14, 0.6, 0.7, 0.4, 0.3}, network.Weights, 1e-9)
+}
+
+func (s *TrainerSuite) TestTrainWithObservingSchedule() {
+	var initializerMock = new(mockInitializer)
+	initializerMock.On("Initialize", mock.AnythingOfType("[]float64")).Run(func(args mock.Arguments) {
+		copy(args.Get(0).([]float64), []float64{0.3, 0.5, 0.7, 0.2, 0.6, 0.7, 0.4, 0.3})
+	})
+	var (
+		schedule   = &observingSchedule{ConstantRate: 0.6}
+		sampler    = sampling.New[[]float64](sampling.NewSliceSource([][]float64{{1, 0, 1, 0}}), new(sampling.SystematicalStrategy[[]float64]))
+		trainer    = NewTrainer(sampler, schedule, neighbor.Identity(), WithInitializer(initializerMock))
+		network, _ = New(4, []int{2})
+	)
+	s.NoError(network.Init())
+	s.NoError(trainer.Train(context.TODO(), network, 2))
+	s.Equal([]int{1, 2}, schedule.epochs)
+	s.InDeltaSlice([]float64{math.Sqrt(0.87), math.Sqrt(0.1392)}, schedule.losses, 1e-9)
+}
+
+func (s *TrainerSuite) TestTrainEndReportsLossOfLastEpoch() {
+	var initializerMock = new(mockInitializer)
+	initializerMock.On("Initialize", mock.AnythingOfType("[]float64")).Run(func(args mock.Arguments) {
+		copy(args.Get(0).([]float64), []float64{0.3, 0.5, 0.7, 0.2, 0.6, 0.7, 0.4, 0.3})
+	})
+	var (
+		end        = new(trainEnd)
+		source     = sampling.NewSliceSource([][]float64{{1, 0, 1, 0}})
+		trainer    = NewTrainer(sampling.New[[]float64](source, new(sampling.SystematicalStrategy[[]float64])), learning.ConstantRate(0.6), neighbor.Identity(), WithInitializer(initializerMock), WithCallbacks(end))
+		network, _ = New(4, []int{2})
+	)
+	s.NoError(network.Init())
+	s.NoError(trainer.Train(context.TODO(), network, 2))
+	s.InDelta(math.Sqrt(0.1392), end.loss, 1e-9)
+
+	end.loss = 0
+	s.NoError(trainer.TrainOnline(context.TODO(), network, sampling.New[[]float64](source, new(sampling.SystematicalStrategy[[]float64])), 1))
+	s.NotZero(end.loss)
+}
+
+func (s *TrainerSuite) TestTrainWithEarlyStopping() {
+	var initializerMock = new(mockInitializer)
+	initializerMock.On("Initialize", mock.AnythingOfType("[]float64")).Run(func(args mock.Arguments) {
+		copy(args.Get(0).([]float64), []float64{0.3, 0.5, 0.7, 0.2, 0.6, 0.7, 0.4, 0.3})
+	})
+	var (
+		epochs     int
+		history    = new(bytes.Buffer)
+		stopping   = callbacks.NewEarlyStopping(0, callbacks.WithMinDelta(1))
+		counter    = &epochCounter{count: &epochs}
+		sampler    = sampling.New[[]float64](sampling.NewSliceSource([][]float64{{1, 0, 1, 0}}), new(sampling.SystematicalStrategy[[]float64]))
+		trainer    = NewTrainer(sampler, learning.ConstantRate(0.6), neighbor.Identity(), WithInitializer(initializerMock), WithCallbacks(counter, callbacks.NewHistory(history), stopping))
+		network, _ = New(4, []int{2})
+	)
+	s.NoError(network.Init())
+	s.NoError(trainer.Train(context.TODO(), network, 5))
+	s.Equal(2, epochs)
+	s.InDelta(math.Sqrt(0.87), stopping.Best(), 1e-9)
+	s.Equal(3, strings.Count(history.String(), "\n"))
+}
+
+func (s *TrainerSuite) TestResume() {
+	var (
+		schedule    = &observingSchedule{ConstantRate: 0.6}
+		checkpoints []Checkpoint
+		sampler     = sampling.New[[]float64](sampling.NewSliceSource([][]float64{{1, 0, 1, 0}}), new(sampling.SystematicalStrategy[[]float64]))
+		trainer     = NewTrainer(sampler, schedule, neighbor.Identity(), WithCheckpoints(2, func(_ context.Context, _ *Network, c Checkpoint) error {
+			checkpoints = append(checkpoints, c)
+			return nil
+		}))
+		network, _ = New(4, []int{2}, WithWeights([]float64{0.3, 0.5, 0.7, 0.2, 0.6, 0.7, 0.4, 0.3}))
+	)
+	s.NoError(network.Init())
+	s.ErrorIs(trainer.Resume(context.TODO(), network, Checkpoint{Epoch: -1}, 5), errors.InvalidParameterValueError)
+	s.NoError(trainer.Resume(context.TODO(), network, Checkpoint{Epoch: 2}, 5))
+	s.Equal([]int{3, 4, 5}, schedule.epochs)
+	s.Equal([]Checkpoint{{Epoch: 4}, {Epoch: 5}}, checkpoints)
+}
+
+func (s *TrainerSuite) TestTrainOnline() {
+	var tests = []struct {
+		Name        string
+		Count       int
+		Every       int
+		Epochs      []int
+		Checkpoints []Checkpoint
+	}{
+		{
+			Name:        "When the stream ends within a period then the last period is shorter",
+			Count:       5,
+			Every:       2,
+			Epochs:      []int{1, 2, 3},
+			Checkpoints: []Checkpoint{{Epoch: 2}, {Epoch: 3}},
+		},
+		{
+			Name:        "When the stream ends with a period then the last period is saved",
+			Count:       4,
+			Every:       3,
+			Epochs:      []int{1, 2},
+			Checkpoints: []Checkpoint{{Epoch: 2}},
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.Name, func() {
+			var (
+				i           int
+				epochs      int
+				schedule    = &observingSchedule{ConstantRate: 0.5}
+				checkpoints []Checkpoint
+				stream      = sampling.NewGeneratorStream(func(context.Context) ([]float64, error) {
+					if i++; i > test.Count {
+						return nil, io.EOF
+					}
+					return []float64{float64(i % 2), 1}, nil
+				})
+				trainer = NewTrainer(nil, schedule, neighbor.Identity(), WithCallbacks(&epochCounter{count: &epochs}), WithCheckpoints(test.Every, func(_ context.Context, _ *Network, c Checkpoint) error {
+					checkpoints = append(checkpoints, c)
+					return nil
+				}))
+				network, _ = New(2, []int{2}, WithWeights([]float64{0.3, 0.5, 0.7, 0.2}))
+			)
+			s.NoError(network.Init())
+			s.NoError(trainer.TrainOnline(context.TODO(), network, stream, 2))
+			s.Equal(test.Epochs, schedule.epochs)
+			s.Equal(len(test.Epochs), epochs)
+			s.Equal(test.Checkpoints, checkpoints)
+		})
+	}
+}
+
+func (s *TrainerSuite) TestTrainOnlineMatchesTrain() {
+	var initializerMock = new(mockInitializer)
+	initializerMock.On("Initialize", mock.AnythingOfType("[]float64"))
+	var (
+		source     = sampling.NewSliceSource([][]float64{{1, 0}, {0, 1}, {1, 1}})
+		weights    = []float64{0.3, 0.5, 0.7, 0.2}
+		trainer    = NewTrainer(sampling.New[[]float64](source, new(sampling.SystematicalStrategy[[]float64])), learning.ConstantRate(0.5), neighbor.Identity(), WithInitializer(initializerMock))
+		expected   = func() *Network { n, _ := New(2, []int{2}, WithWeights(slices.Clone(weights))); return n }()
+		network, _ = New(2, []int{2}, WithWeights(slices.Clone(weights)))
+	)
+	s.NoError(expected.Init())
+	s.NoError(network.Init())
+	s.NoError(trainer.Train(context.TODO(), expected, 1))
+	s.NoError(trainer.TrainOnline(context.TODO(), network, sampling.New[[]float64](source, new(sampling.SystematicalStrategy[[]float64])), 10))
+	s.Equal(expected.Weights, network.Weights)
+	s.ErrorIs(trainer.TrainOnline(context.TODO(), network, nil, 0), errors.InvalidParameterValueError)
+}
+
+func (s *TrainerSuite) TestTrainOnlineStopsEndlessStream() {
+	var (
+		stream = sampling.NewGeneratorStream(func(context.Context) ([]float64, error) {
+			return []float64{1, 1}, nil
+		})
+		stopping   = callbacks.NewEarlyStopping(1)
+		trainer    = NewTrainer(nil, learning.ConstantRate(0.5), neighbor.Identity(), WithCallbacks(stopping))
+		network, _ = New(2, []int{1}, WithWeights([]float64{1, 1}))
+	)
+	s.NoError(network.Init())
+	s.NoError(trainer.TrainOnline(context.TODO(), network, stream, 5))
+	s.Zero(stopping.Best())
+}
+
+// trainEnd records the loss reported at the end of training
+type trainEnd struct {
+	callbacks.Base
+	loss float64
+}
+
+func (c *trainEnd) OnTrainEnd(_ context.Context, e callbacks.Event) error {
+	c.loss = e.Loss
+	return nil
+}
+
+// epochCounter counts finished epochs
+type epochCounter struct {
+	callbacks.Base
+	count *int
+}
+
+func (c *epochCounter) OnEpochEnd(context.Context, callbacks.Event) error {
+	*c.count++
+	return nil
+}
+
+// observingSchedule records quantization errors passed by the trainer
+type observingSchedule struct {
+	learning.ConstantRate
+	epochs []int
+	losses []float64
+}
+
+func (o *observingSchedule) Observe(epoch int, loss float64) {
+	o.epochs = append(o.epochs, epoch)
+	o.losses = append(o.losses, loss)
+}
+
 type mockInitializer struct {
 	mock.Mock
 }
@@ -0,0 +1,86 @@
+package callbacks
+
+import (
+	"context"
+	"time"
+)
+
+// Event describes the state of training passed to callbacks
+type Event struct {
+	// Epoch is the current epoch counted from 1
+	Epoch int
+	// Batch is the number of the batch within the epoch counted from 1. It is zero for epoch events
+	Batch int
+	// Rate is the learning rate of the epoch
+	Rate float64
+	// Loss is the mean loss of the batch or epoch. Self-organizing maps report quantization error
+	Loss float64
+	// Elapsed is the time since the training started
+	Elapsed time.Duration
+	// Weights are the weights of the trained network. Callbacks must not keep the slice
+	Weights []float64
+}
+
+// Callback receives events of training. Returning errors.StopTrainingError stops training gracefully,
+// any other error aborts it
+type Callback interface {
+	OnEpochStart(context.Context, Event) error
+	OnEpochEnd(context.Context, Event) error
+	OnBatchEnd(context.Context, Event) error
+	OnTrainEnd(context.Context, Event) error
+}
+
+// Base implements Callback doing nothing. It is meant to be embedded by callbacks handling only some events
+type Base struct{}
+
+func (Base) OnEpochStart(context.Context, Event) error {
+	return nil
+}
+
+func (Base) OnEpochEnd(context.Context, Event) error {
+	return nil
+}
+
+func (Base) OnBatchEnd(context.Context, Event) error {
+	return nil
+}
+
+func (Base) OnTrainEnd(context.Context, Event) error {
+	return nil
+}
+
+// List notifies callbacks in order. It stops at the first error
+type List []Callback
+
+func (l List) OnEpochStart(ctx context.Context, e Event) error {
+	return l.each(func(c Callback) error {
+		return c.OnEpochStart(ctx, e)
+	})
+}
+
+func (l List) OnEpochEnd(ctx context.Context, e Event) error {
+	return l.each(func(c Callback) error {
+		return c.OnEpochEnd(ctx, e)
+	})
+}
+
+func (l List) OnBatchEnd(ctx context.Context, e Event) error {
+	return l.each(func(c Callback) error {
+		return c.OnBatchEnd(ctx, e)
+	})
+}
+
+func (l List) OnTrainEnd(ctx context.Context, e Event) error {
+	return l.each(func(c Callback) error {
+		return c.OnTrainEnd(ctx, e)
+	})
+}
+
+func (l List) each(f func(Callback) error) (err error) {
+	for _, c := range l {
+		if err = f(c); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,154 @@
+package callbacks
+
+import (
+	"bytes"
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// recorder records names of received events
+type recorder struct {
+	events []string
+	err    error
+}
+
+func (r *recorder) OnEpochStart(context.Context, Event) error {
+	r.events = append(r.events, "epoch start")
+	return r.err
+}
+
+func (r *recorder) OnEpochEnd(context.Context, Event) error {
+	r.events = append(r.events, "epoch end")
+	return r.err
+}
+
+func (r *recorder) OnBatchEnd(context.Context, Event) error {
+	r.events = append(r.events, "batch end")
+	return r.err
+}
+
+func (r *recorder) OnTrainEnd(context.Context, Event) error {
+	r.events = append(r.events, "train end")
+	return r.err
+}
+
+func TestList(t *testing.T) {
+	var (
+		ctx    = context.TODO()
+		first  = &recorder{}
+		failed = &recorder{err: errors.StopTrainingError}
+		last   = &recorder{}
+		list   = List{first, failed, last}
+	)
+	assert.NoError(t, List{first}.OnEpochStart(ctx, Event{}))
+	assert.ErrorIs(t, list.OnEpochEnd(ctx, Event{}), errors.StopTrainingError)
+	assert.ErrorIs(t, list.OnBatchEnd(ctx, Event{}), errors.StopTrainingError)
+	assert.ErrorIs(t, list.OnTrainEnd(ctx, Event{}), errors.StopTrainingError)
+	assert.Equal(t, []string{"epoch start", "epoch end", "batch end", "train end"}, first.events)
+	assert.Equal(t, []string{"epoch end", "batch end", "train end"}, failed.events)
+	assert.Empty(t, last.events)
+}
+
+func TestLogger(t *testing.T) {
+	var (
+		ctx    = context.TODO()
+		buffer = new(bytes.Buffer)
+		logger = NewLogger(slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		event  = Event{Epoch: 2, Batch: 3, Rate: 0.1, Loss: 0.5, Elapsed: time.Second}
+	)
+	assert.NoError(t, logger.OnBatchEnd(ctx, event))
+	assert.Empty(t, buffer.String())
+
+	assert.NoError(t, logger.OnEpochEnd(ctx, event))
+	assert.Contains(t, buffer.String(), `msg="epoch finished" epoch=2 rate=0.1 loss=0.5 elapsed=1s`)
+
+	assert.NoError(t, logger.OnTrainEnd(ctx, event))
+	assert.Contains(t, buffer.String(), `msg="training finished" epochs=2 loss=0.5 elapsed=1s`)
+
+	buffer.Reset()
+	assert.NoError(t, NewLogger(slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug})), WithBatches()).OnBatchEnd(ctx, event))
+	assert.Contains(t, buffer.String(), `msg="batch finished" epoch=2 batch=3 loss=0.5`)
+}
+
+func TestHistory(t *testing.T) {
+	var (
+		ctx     = context.TODO()
+		buffer  = new(bytes.Buffer)
+		history = NewHistory(buffer)
+	)
+	assert.NoError(t, history.OnEpochEnd(ctx, Event{Epoch: 1, Rate: 0.1, Loss: 0.5, Elapsed: 1500 * time.Millisecond}))
+	assert.NoError(t, history.OnBatchEnd(ctx, Event{Epoch: 2, Batch: 1}))
+	assert.NoError(t, history.OnEpochEnd(ctx, Event{Epoch: 2, Rate: 0.05, Loss: 0.25, Elapsed: 3 * time.Second}))
+	assert.Equal(t, "epoch,rate,loss,elapsed\n1,0.1,0.5,1.5\n2,0.05,0.25,3\n", buffer.String())
+}
+
+func TestEarlyStopping(t *testing.T) {
+	var tests = []struct {
+		Name     string
+		Stopping *EarlyStopping
+		Losses   []float64
+		// Stopped is the index of the epoch which stops training or -1
+		Stopped  int
+		Expected []float64
+	}{
+		{
+			Name:     "When loss improves then training continues",
+			Stopping: NewEarlyStopping(1),
+			Losses:   []float64{3, 2, 1},
+			Stopped:  -1,
+			Expected: []float64{2},
+		},
+		{
+			Name:     "When loss does not improve for patience epochs then training stops",
+			Stopping: NewEarlyStopping(2),
+			Losses:   []float64{3, 2, 2.5, 2, 1},
+			Stopped:  3,
+			Expected: []float64{3},
+		},
+		{
+			Name:     "When improvement is smaller than minimum delta then training stops",
+			Stopping: NewEarlyStopping(1, WithMinDelta(0.5)),
+			Losses:   []float64{3, 2.8},
+			Stopped:  1,
+			Expected: []float64{1},
+		},
+		{
+			Name:     "When best weights are restored then weights of the best epoch are kept",
+			Stopping: NewEarlyStopping(2, WithRestoreBest()),
+			Losses:   []float64{3, 1, 2, 2},
+			Stopped:  3,
+			Expected: []float64{1},
+		},
+		{
+			Name:     "When monitored value is set then it decides about improvement",
+			Stopping: NewEarlyStopping(1, WithMonitor(func(e Event) float64 { return -e.Rate })),
+			Losses:   []float64{1, 1, 1},
+			Stopped:  -1,
+			Expected: []float64{2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var (
+				ctx     = context.TODO()
+				weights = []float64{0}
+				stopped = -1
+			)
+			for i, loss := range test.Losses {
+				weights[0] = float64(i)
+				if err := test.Stopping.OnEpochEnd(ctx, Event{Epoch: i + 1, Rate: float64(i), Loss: loss, Weights: weights}); err != nil {
+					assert.ErrorIs(t, err, errors.StopTrainingError)
+					stopped = i
+					break
+				}
+			}
+			assert.Equal(t, test.Stopped, stopped)
+			assert.NoError(t, test.Stopping.OnTrainEnd(ctx, Event{Weights: weights}))
+			assert.Equal(t, test.Expected, weights)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package callbacks
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+var historyHeader = []string{"epoch", "rate", "loss", "elapsed"}
+
+// History writes a CSV row with epoch, learning rate, loss and elapsed seconds after each epoch
+type History struct {
+	Base
+	writer *csv.Writer
+	header bool
+}
+
+func NewHistory(w io.Writer) *History {
+	return &History{
+		writer: csv.NewWriter(w),
+	}
+}
+
+func (h *History) OnEpochEnd(_ context.Context, e Event) error {
+	if !h.header {
+		if err := h.writer.Write(historyHeader); err != nil {
+			return err
+		}
+		h.header = true
+	}
+	if err := h.writer.Write([]string{
+		strconv.Itoa(e.Epoch),
+		strconv.FormatFloat(e.Rate, 'g', -1, 64),
+		strconv.FormatFloat(e.Loss, 'g', -1, 64),
+		strconv.FormatFloat(e.Elapsed.Seconds(), 'g', -1, 64),
+	}); err != nil {
+		return err
+	}
+	h.writer.Flush()
+	return h.writer.Error()
+}
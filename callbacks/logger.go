@@ -0,0 +1,64 @@
+package callbacks
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logger logs progress of training with slog
+type Logger struct {
+	Base
+	logger  *slog.Logger
+	batches bool
+}
+
+type LoggerOption func(*Logger)
+
+// WithBatches enables logging of each batch on debug level
+func WithBatches() LoggerOption {
+	return func(l *Logger) {
+		l.batches = true
+	}
+}
+
+// NewLogger creates callback logging with given logger or with slog.Default when logger is nil
+func NewLogger(logger *slog.Logger, opts ...LoggerOption) (l *Logger) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	l = &Logger{
+		logger: logger,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return
+}
+
+func (l *Logger) OnEpochEnd(ctx context.Context, e Event) error {
+	l.logger.InfoContext(ctx, "epoch finished",
+		slog.Int("epoch", e.Epoch),
+		slog.Float64("rate", e.Rate),
+		slog.Float64("loss", e.Loss),
+		slog.Duration("elapsed", e.Elapsed))
+	return nil
+}
+
+func (l *Logger) OnBatchEnd(ctx context.Context, e Event) error {
+	if !l.batches {
+		return nil
+	}
+	l.logger.DebugContext(ctx, "batch finished",
+		slog.Int("epoch", e.Epoch),
+		slog.Int("batch", e.Batch),
+		slog.Float64("loss", e.Loss))
+	return nil
+}
+
+func (l *Logger) OnTrainEnd(ctx context.Context, e Event) error {
+	l.logger.InfoContext(ctx, "training finished",
+		slog.Int("epochs", e.Epoch),
+		slog.Float64("loss", e.Loss),
+		slog.Duration("elapsed", e.Elapsed))
+	return nil
+}
@@ -0,0 +1,91 @@
+package callbacks
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"math"
+)
+
+// Monitor selects the value of the event watched by EarlyStopping
+type Monitor func(Event) float64
+
+// Loss monitors the loss of the epoch
+func Loss(e Event) float64 {
+	return e.Loss
+}
+
+// EarlyStopping stops training when the monitored value does not decrease by more than minimum delta
+// for patience epochs. It can restore the weights of the best epoch when training ends
+type EarlyStopping struct {
+	Base
+	monitor  Monitor
+	patience int
+	delta    float64
+	restore  bool
+	best     float64
+	// bad is the number of epochs without improvement
+	bad     int
+	weights []float64
+}
+
+type EarlyStoppingOption func(*EarlyStopping)
+
+// WithMonitor sets monitored value. The default is Loss. Values to be maximized should be negated
+func WithMonitor(monitor Monitor) EarlyStoppingOption {
+	return func(s *EarlyStopping) {
+		s.monitor = monitor
+	}
+}
+
+// WithMinDelta sets minimum decrease of the monitored value considered as improvement
+func WithMinDelta(delta float64) EarlyStoppingOption {
+	return func(s *EarlyStopping) {
+		s.delta = math.Abs(delta)
+	}
+}
+
+// WithRestoreBest restores the weights of the best epoch when training ends
+func WithRestoreBest() EarlyStoppingOption {
+	return func(s *EarlyStopping) {
+		s.restore = true
+	}
+}
+
+func NewEarlyStopping(patience int, opts ...EarlyStoppingOption) (s *EarlyStopping) {
+	s = &EarlyStopping{
+		monitor:  Loss,
+		patience: max(0, patience),
+		best:     math.Inf(1),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return
+}
+
+// Best returns the best monitored value
+func (s *EarlyStopping) Best() float64 {
+	return s.best
+}
+
+func (s *EarlyStopping) OnEpochEnd(_ context.Context, e Event) error {
+	if value := s.monitor(e); value < s.best-s.delta {
+		s.best = value
+		s.bad = 0
+		if s.restore {
+			s.weights = append(s.weights[:0], e.Weights...)
+		}
+		return nil
+	}
+	if s.bad++; s.bad >= s.patience {
+		return errors.WithMessagef(errors.StopTrainingError, "no improvement in %d epochs", s.bad)
+	}
+	return nil
+}
+
+func (s *EarlyStopping) OnTrainEnd(_ context.Context, e Event) error {
+	if s.restore && len(s.weights) == len(e.Weights) {
+		copy(e.Weights, s.weights)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package mlp
+
+import (
+	"context"
+	"github.com/publiczny81/ml/activate"
+	"github.com/publiczny81/ml/ann/initializers"
+	"github.com/publiczny81/ml/ann/mlp"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/learning"
+	"github.com/publiczny81/ml/losses"
+	"github.com/publiczny81/ml/optimizers"
+	"github.com/publiczny81/ml/sampling"
+	"github.com/publiczny81/ml/utils"
+	"github.com/stretchr/testify/assert"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResumeFromCheckpoint(t *testing.T) {
+	const epochs = 12
+	var (
+		ctx     = context.TODO()
+		path    = filepath.Join(t.TempDir(), "mlp.json")
+		samples = sampling.NewSliceSource([][][]float64{{{0, 0}, {0}}, {{0, 1}, {1}}, {{1, 0}, {1}}, {{1, 1}, {0}}})
+		train   = func(seed uint64, opts ...mlp.TrainerOption) *mlp.Trainer {
+			var (
+				random   = utils.NewPCG(seed)
+				sampler  = sampling.New[[][]float64](samples, sampling.NewRandomStrategy[[][]float64](utils.Rand))
+				schedule = learning.NewReduceOnPlateau(0.1, learning.WithPatience(1), learning.WithFactor(0.5))
+			)
+			opts = append(opts,
+				mlp.WithRand(random),
+				mlp.WithOptimizer(optimizers.NewAdam(0.9, 0.999, 1e-8)),
+				mlp.WithInitializer(initializers.NewUniform(rand.New(rand.NewPCG(1, 2)))))
+			return mlp.NewTrainer(sampler, schedule, losses.MeanSquareError[float64], opts...)
+		}
+		newNetwork = func() *mlp.Network {
+			var network, err = mlp.New(2, mlp.AddLayer(3, activate.Tanh), mlp.AddLayer(1, activate.Sigmoid))
+			assert.NoError(t, err)
+			assert.NoError(t, network.Init())
+			return network
+		}
+		continuous  = newNetwork()
+		interrupted = newNetwork()
+		resumed     = new(mlp.Network)
+	)
+	assert.NoError(t, train(42).Train(ctx, continuous, epochs))
+	assert.NoError(t, train(42, mlp.WithCheckpoints(4, SaveCheckpoint(path))).Train(ctx, interrupted, 5))
+
+	checkpoint, err := LoadCheckpoint(path, resumed)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, checkpoint.Epoch)
+	assert.NotEmpty(t, checkpoint.Schedule)
+	assert.NotEmpty(t, checkpoint.Rand)
+	assert.NotNil(t, checkpoint.Optimizer)
+	assert.NoError(t, resumed.Init())
+
+	assert.NoError(t, train(7).Resume(ctx, resumed, checkpoint, epochs))
+	assert.Equal(t, continuous.Options.Weights, resumed.Options.Weights)
+}
+
+func TestLoadCheckpoint(t *testing.T) {
+	var (
+		path    = filepath.Join(t.TempDir(), "mlp.json")
+		network = testNetwork(t)
+		saver   = SaveCheckpoint(path)
+		state   = optimizers.NewSGD().State()
+	)
+	_, err := LoadCheckpoint(path, new(mlp.Network))
+	assert.Error(t, err)
+
+	assert.NoError(t, saver(context.TODO(), network, mlp.Checkpoint{Epoch: 3, Rand: []byte{1, 2}, Optimizer: &state}))
+	checkpoint, err := LoadCheckpoint(path, new(mlp.Network))
+	assert.NoError(t, err)
+	assert.Equal(t, mlp.Checkpoint{Epoch: 3, Rand: []byte{1, 2}, Optimizer: &state}, checkpoint)
+
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	assert.NoError(t, NewEncoder(file).Encode(network))
+	assert.NoError(t, file.Close())
+	_, err = LoadCheckpoint(path, new(mlp.Network))
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+}
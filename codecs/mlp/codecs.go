@@ -4,9 +4,21 @@ import (
 	"encoding/json"
 	"github.com/publiczny81/ml/ann/mlp"
 	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/optimizers"
+	"github.com/publiczny81/ml/preprocessing"
 	"io"
 )
 
+// Bundle groups the network with the optimizer used to train it and the checkpoint of its training,
+// so the training can be resumed exactly, and with preprocessing of its inputs
+type Bundle struct {
+	Network    *mlp.Network
+	Optimizer  *optimizers.Optimizer
+	Checkpoint *mlp.Checkpoint
+	// Preprocessing contains fitted preprocessors of inputs of the network
+	Preprocessing preprocessing.Pipeline
+}
+
 type Encoder struct {
 	writer io.Writer
 }
@@ -23,17 +35,54 @@ func (enc *Encoder) Encode(v any) (err error) {
 		return enc.encode(value)
 	case mlp.Network:
 		return enc.encode(&value)
+	case *Bundle:
+		if value == nil {
+			return errors.WithMessage(errors.InvalidParameterValueError, "bundle is nil")
+		}
+		return enc.encodeBundle(value)
+	case Bundle:
+		return enc.encodeBundle(&value)
 	default:
-		err = errors.WithMessagef(errors.InvalidParameterValueError, "v is neither *mlp.Network nor mlp.Network")
+		err = errors.WithMessagef(errors.InvalidParameterValueError, "v is neither *mlp.Network, mlp.Network nor Bundle")
 		return
 	}
 }
 
 func (enc *Encoder) encode(network *mlp.Network) error {
+	var net, err = newNetwork(network)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(enc.writer).Encode(net)
+}
+
+func (enc *Encoder) encodeBundle(bundle *Bundle) error {
+	var net, err = newNetwork(bundle.Network)
+	if err != nil {
+		return err
+	}
+	net.Preprocessing = bundle.Preprocessing
+	if c := bundle.Checkpoint; c != nil {
+		net.Optimizer = c.Optimizer
+		net.Checkpoint = &Checkpoint{
+			Epoch:    c.Epoch,
+			Schedule: c.Schedule,
+			Rand:     c.Rand,
+		}
+	}
+	if bundle.Optimizer != nil {
+		var state = bundle.Optimizer.State()
+		net.Optimizer = &state
+	}
+	return json.NewEncoder(enc.writer).Encode(net)
+}
+
+func newNetwork(network *mlp.Network) (net *Network, err error) {
 	if network == nil {
-		return errors.WithMessage(errors.InvalidParameterValueError, "network is nil")
+		err = errors.WithMessage(errors.InvalidParameterValueError, "network is nil")
+		return
 	}
-	var net = new(Network)
+	net = new(Network)
 	net.Input = network.Input
 	net.Weights = network.Options.Weights
 	for _, l := range network.Options.Layers {
@@ -45,8 +94,7 @@ func (enc *Encoder) encode(network *mlp.Network) error {
 			Activation: l.Activation,
 		})
 	}
-
-	return json.NewEncoder(enc.writer).Encode(net)
+	return
 }
 
 type Decoder struct {
@@ -63,8 +111,10 @@ func (d *Decoder) Decode(v any) (err error) {
 	switch value := v.(type) {
 	case *mlp.Network:
 		return d.decode(value)
+	case *Bundle:
+		return d.decodeBundle(value)
 	default:
-		err = errors.WithMessagef(errors.InvalidParameterValueError, "v must be *mlp.Network")
+		err = errors.WithMessagef(errors.InvalidParameterValueError, "v must be either *mlp.Network or *Bundle")
 		return
 	}
 }
@@ -77,6 +127,41 @@ func (d *Decoder) decode(network *mlp.Network) error {
 	if err := json.NewDecoder(d.reader).Decode(net); err != nil {
 		return err
 	}
+	if err := net.validate(); err != nil {
+		return err
+	}
+	setNetwork(network, net)
+	return nil
+}
+
+func (d *Decoder) decodeBundle(bundle *Bundle) error {
+	if bundle == nil || bundle.Network == nil {
+		return errors.WithMessage(errors.InvalidParameterValueError, "network is nil")
+	}
+	var net = new(Network)
+	if err := json.NewDecoder(d.reader).Decode(net); err != nil {
+		return err
+	}
+	if err := net.validate(); err != nil {
+		return err
+	}
+	setNetwork(bundle.Network, net)
+	bundle.Preprocessing = net.Preprocessing
+	if c := net.Checkpoint; c != nil {
+		bundle.Checkpoint = &mlp.Checkpoint{
+			Epoch:     c.Epoch,
+			Schedule:  c.Schedule,
+			Rand:      c.Rand,
+			Optimizer: net.Optimizer,
+		}
+	}
+	if bundle.Optimizer != nil && net.Optimizer != nil {
+		return bundle.Optimizer.SetState(*net.Optimizer)
+	}
+	return nil
+}
+
+func setNetwork(network *mlp.Network, net *Network) {
 	network.Options.Input = net.Input
 	for _, l := range net.Layers {
 		network.Options.Layers = append(network.Options.Layers, mlp.LayerSpec{
@@ -85,5 +170,4 @@ func (d *Decoder) decode(network *mlp.Network) error {
 		})
 	}
 	network.Options.Weights = net.Weights
-	return nil
 }
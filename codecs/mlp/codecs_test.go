@@ -1 +1,143 @@
 package mlp
+
+import (
+	"bytes"
+	"context"
+	"github.com/publiczny81/ml/activate"
+	"github.com/publiczny81/ml/ann/mlp"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/optimizers"
+	"github.com/publiczny81/ml/preprocessing"
+	"github.com/publiczny81/ml/sampling"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	var tests = []struct {
+		Name  string
+		Input any
+		Error error
+	}{
+		{
+			Name:  "When passed value is nil then return the error",
+			Input: (*mlp.Network)(nil),
+			Error: errors.InvalidParameterValueError,
+		},
+		{
+			Name:  "When passed bundle is nil then return the error",
+			Input: (*Bundle)(nil),
+			Error: errors.InvalidParameterValueError,
+		},
+		{
+			Name:  "When passed bundle has no network then return the error",
+			Input: Bundle{Optimizer: optimizers.NewSGD()},
+			Error: errors.InvalidParameterValueError,
+		},
+		{
+			Name:  "When passed value is neither network nor bundle then return the error",
+			Input: struct{}{},
+			Error: errors.InvalidParameterValueError,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var err = NewEncoder(new(bytes.Buffer)).Encode(test.Input)
+			assert.ErrorIs(t, err, test.Error)
+		})
+	}
+}
+
+func TestDecoderWithVectorActivation(t *testing.T) {
+	var (
+		buffer     = new(bytes.Buffer)
+		network, _ = mlp.New(2, mlp.AddLayer(3, activate.Softmax), mlp.WithWeights([]float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}))
+		actual     = new(mlp.Network)
+		input      = []float64{1, -1}
+	)
+	assert.NoError(t, network.Init())
+	expected, err := network.Predict(context.TODO(), input, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, NewEncoder(buffer).Encode(network))
+	assert.NoError(t, NewDecoder(buffer).Decode(actual))
+	assert.Equal(t, activate.Softmax, actual.Options.Layers[0].Activation)
+	assert.NoError(t, actual.Init())
+
+	actualOutput, err := actual.Predict(context.TODO(), input, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actualOutput)
+}
+
+func TestBundle(t *testing.T) {
+	var (
+		buffer    = new(bytes.Buffer)
+		network   = testNetwork(t)
+		optimizer = optimizers.NewMomentum(0.9)
+		actual    = &Bundle{
+			Network:   new(mlp.Network),
+			Optimizer: optimizers.NewMomentum(0.9),
+		}
+	)
+	optimizer.Step(network.Options.Weights, []float64{1, 2, 3, 4}, 0.1)
+
+	assert.NoError(t, NewEncoder(buffer).Encode(Bundle{Network: network, Optimizer: optimizer}))
+	assert.NoError(t, NewDecoder(buffer).Decode(actual))
+
+	assert.Equal(t, network.Options, actual.Network.Options)
+	assert.Equal(t, optimizer.State(), actual.Optimizer.State())
+}
+
+func TestBundleWithOtherOptimizer(t *testing.T) {
+	var (
+		buffer    = new(bytes.Buffer)
+		network   = testNetwork(t)
+		optimizer = optimizers.NewMomentum(0.9)
+	)
+	optimizer.Step(network.Options.Weights, []float64{1, 2, 3, 4}, 0.1)
+
+	assert.NoError(t, NewEncoder(buffer).Encode(&Bundle{Network: network, Optimizer: optimizer}))
+	assert.ErrorIs(t, NewDecoder(buffer).Decode(&Bundle{
+		Network:   new(mlp.Network),
+		Optimizer: optimizers.NewAdam(0.9, 0.999, 1e-8),
+	}), errors.InvalidParameterValueError)
+}
+
+func TestBundleWithPreprocessing(t *testing.T) {
+	var (
+		buffer   = new(bytes.Buffer)
+		scaler   = preprocessing.NewStandardScaler()
+		actual   = &Bundle{Network: new(mlp.Network)}
+		pipeline = preprocessing.Pipeline{scaler}
+	)
+	assert.NoError(t, scaler.Fit(context.TODO(), sampling.NewSliceSource([][]float64{{1}, {3}})))
+	assert.NoError(t, NewEncoder(buffer).Encode(Bundle{Network: testNetwork(t), Preprocessing: pipeline}))
+	assert.NoError(t, NewDecoder(buffer).Decode(actual))
+	assert.Equal(t, pipeline, actual.Preprocessing)
+}
+
+func testNetwork(t *testing.T) *mlp.Network {
+	var network, err = mlp.New(1, mlp.AddLayer(2, activate.Sigmoid), mlp.WithWeights([]float64{0.1, 0.2, 0.3, 0.4}))
+	assert.NoError(t, err)
+	return network
+}
+
+func TestDecoderWithUnknownActivation(t *testing.T) {
+	var (
+		buffer     = bytes.NewBufferString(`{"input":1,"layers":[{"activation":"unknown@1","neurons":1}],"weights":[1,2]}`)
+		network    = new(mlp.Network)
+		err        = NewDecoder(buffer).Decode(network)
+		bundleErr  = NewDecoder(bytes.NewBufferString(`{"input":1,"layers":[{"activation":"unknown","neurons":1}]}`)).Decode(&Bundle{Network: new(mlp.Network)})
+		registered = NewDecoder(bytes.NewBufferString(`{"input":1,"layers":[{"activation":"elu@0.5","neurons":1}],"weights":[1,2]}`)).Decode(new(mlp.Network))
+	)
+	assert.ErrorIs(t, err, errors.UnknownNameError)
+	assert.ErrorContains(t, err, "layers[0].activation=unknown@1")
+	assert.ErrorIs(t, bundleErr, errors.UnknownNameError)
+	assert.NoError(t, registered)
+}
+
+func TestDecoderWithMalformedActivation(t *testing.T) {
+	var err = NewDecoder(bytes.NewBufferString(`{"input":1,"layers":[{"activation":"sigmoid","neurons":1},{"activation":"rectifier","neurons":1}]}`)).Decode(new(mlp.Network))
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+	assert.ErrorContains(t, err, "layers[1].activation=rectifier")
+}
@@ -1,12 +1,41 @@
 package mlp
 
+import (
+	"github.com/publiczny81/ml/activate"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/optimizers"
+	"github.com/publiczny81/ml/preprocessing"
+)
+
 type LayerSpec struct {
 	Activation string `json:"activation"`
 	Neurons    int    `json:"neurons"`
 }
 
 type Network struct {
-	Input   int         `json:"input"`
-	Layers  []LayerSpec `json:"layers,omitempty"`
-	Weights []float64   `json:"weights,omitempty"`
+	Input     int               `json:"input"`
+	Layers    []LayerSpec       `json:"layers,omitempty"`
+	Weights   []float64         `json:"weights,omitempty"`
+	Optimizer *optimizers.State `json:"optimizer,omitempty"`
+	// Checkpoint is the state of training saved together with the network. The state of the optimizer is kept
+	// in Optimizer
+	Checkpoint *Checkpoint `json:"checkpoint,omitempty"`
+	// Preprocessing is applied to inputs before they are passed to the network
+	Preprocessing preprocessing.Pipeline `json:"preprocessing,omitempty"`
+}
+
+type Checkpoint struct {
+	Epoch    int    `json:"epoch"`
+	Schedule []byte `json:"schedule,omitempty"`
+	Rand     []byte `json:"rand,omitempty"`
+}
+
+// validate checks whether activations of all layers are registered and have valid parameters
+func (net *Network) validate() error {
+	for i, l := range net.Layers {
+		if _, err := activate.Get(l.Activation); err != nil {
+			return errors.WithMessagef(err, "layers[%d].activation=%s", i, l.Activation)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,54 @@
+package som
+
+import (
+	"context"
+	"github.com/publiczny81/ml/ann/som"
+	"github.com/publiczny81/ml/errors"
+	"os"
+	"path/filepath"
+)
+
+// SaveCheckpoint returns saver which writes the network with the checkpoint to the file. The file is replaced
+// atomically, so it always holds complete checkpoint even if the process crashes while saving
+func SaveCheckpoint(path string) som.CheckpointSaver {
+	return func(_ context.Context, network *som.Network, checkpoint som.Checkpoint) (err error) {
+		var file *os.File
+		if file, err = os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*"); err != nil {
+			return
+		}
+		defer func() {
+			if err != nil {
+				_ = os.Remove(file.Name())
+			}
+		}()
+		if err = NewEncoder(file).Encode(&Bundle{Network: network, Checkpoint: &checkpoint}); err != nil {
+			_ = file.Close()
+			return
+		}
+		if err = file.Close(); err != nil {
+			return
+		}
+		return os.Rename(file.Name(), path)
+	}
+}
+
+// LoadCheckpoint reads the network and the checkpoint saved with SaveCheckpoint
+func LoadCheckpoint(path string, network *som.Network) (checkpoint som.Checkpoint, err error) {
+	var file *os.File
+	if file, err = os.Open(path); err != nil {
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	var bundle = &Bundle{Network: network}
+	if err = NewDecoder(file).Decode(bundle); err != nil {
+		return
+	}
+	if bundle.Checkpoint == nil {
+		err = errors.WithMessagef(errors.InvalidParameterValueError, "%s has no checkpoint", path)
+		return
+	}
+	checkpoint = *bundle.Checkpoint
+	return
+}
@@ -0,0 +1,82 @@
+package som
+
+import (
+	"context"
+	"github.com/publiczny81/ml/ann/initializers"
+	"github.com/publiczny81/ml/ann/som"
+	"github.com/publiczny81/ml/ann/som/neighbor"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/learning"
+	"github.com/publiczny81/ml/sampling"
+	"github.com/publiczny81/ml/utils"
+	"github.com/stretchr/testify/assert"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResumeFromCheckpoint(t *testing.T) {
+	const epochs = 12
+	var (
+		ctx     = context.TODO()
+		path    = filepath.Join(t.TempDir(), "som.json")
+		samples = sampling.NewSliceSource([][]float64{{1, 0}, {0, 1}, {1, 1}, {0.5, 0.2}, {0.1, 0.9}})
+		train   = func(seed uint64, opts ...som.TrainerOption) (*som.Trainer, *utils.Random) {
+			var (
+				random   = utils.NewPCG(seed)
+				sampler  = sampling.New[[]float64](samples, sampling.NewRandomStrategy[[]float64](utils.Rand))
+				schedule = learning.NewReduceOnPlateau(0.5, learning.WithPatience(1), learning.WithFactor(0.5))
+			)
+			opts = append(opts, som.WithRand(random), som.WithInitializer(initializers.NewUniform(rand.New(rand.NewPCG(1, 2)))))
+			return som.NewTrainer(sampler, schedule, neighbor.Identity(), opts...), random
+		}
+		newNetwork = func() *som.Network {
+			var network, err = som.New(2, []int{3})
+			assert.NoError(t, err)
+			assert.NoError(t, network.Init())
+			return network
+		}
+		continuous  = newNetwork()
+		interrupted = newNetwork()
+		resumed     = new(som.Network)
+	)
+	trainer, _ := train(42)
+	assert.NoError(t, trainer.Train(ctx, continuous, epochs))
+
+	trainer, _ = train(42, som.WithCheckpoints(4, SaveCheckpoint(path)))
+	assert.NoError(t, trainer.Train(ctx, interrupted, 5))
+
+	checkpoint, err := LoadCheckpoint(path, resumed)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, checkpoint.Epoch)
+	assert.NotEmpty(t, checkpoint.Schedule)
+	assert.NotEmpty(t, checkpoint.Rand)
+	assert.NoError(t, resumed.Init())
+
+	trainer, _ = train(7)
+	assert.NoError(t, trainer.Resume(ctx, resumed, checkpoint, epochs))
+	assert.Equal(t, continuous.Weights, resumed.Weights)
+}
+
+func TestLoadCheckpoint(t *testing.T) {
+	var (
+		path       = filepath.Join(t.TempDir(), "som.json")
+		network, _ = som.New(1, []int{2}, som.WithWeights([]float64{1, 2}))
+		saver      = SaveCheckpoint(path)
+	)
+	_, err := LoadCheckpoint(path, new(som.Network))
+	assert.Error(t, err)
+
+	assert.NoError(t, saver(context.TODO(), network, som.Checkpoint{Epoch: 3, Rand: []byte{1, 2}}))
+	checkpoint, err := LoadCheckpoint(path, new(som.Network))
+	assert.NoError(t, err)
+	assert.Equal(t, som.Checkpoint{Epoch: 3, Rand: []byte{1, 2}}, checkpoint)
+
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	assert.NoError(t, NewEncoder(file).Encode(network))
+	assert.NoError(t, file.Close())
+	_, err = LoadCheckpoint(path, new(som.Network))
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+}
@@ -4,9 +4,19 @@ import (
 	"encoding/json"
 	"github.com/publiczny81/ml/ann/som"
 	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/preprocessing"
 	"io"
 )
 
+// Bundle groups the network with the checkpoint of its training, so the training can be resumed,
+// and with preprocessing of its inputs
+type Bundle struct {
+	Network    *som.Network
+	Checkpoint *som.Checkpoint
+	// Preprocessing contains fitted preprocessors of inputs of the network
+	Preprocessing preprocessing.Pipeline
+}
+
 type Encoder struct {
 	writer io.Writer
 }
@@ -23,25 +33,56 @@ func (enc *Encoder) Encode(v any) (err error) {
 		return enc.encode(value)
 	case som.Network:
 		return enc.encode(&value)
+	case *Bundle:
+		if value == nil {
+			return errors.WithMessage(errors.InvalidParameterValueError, "bundle is nil")
+		}
+		return enc.encodeBundle(value)
+	case Bundle:
+		return enc.encodeBundle(&value)
 	default:
-		err = errors.WithMessagef(errors.InvalidParameterValueError, "v is neither *som.Network nor som.Network")
+		err = errors.WithMessagef(errors.InvalidParameterValueError, "v is neither *som.Network, som.Network nor Bundle")
 		return
 	}
 }
 
 func (enc *Encoder) encode(network *som.Network) error {
+	var net, err = newNetwork(network)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(enc.writer).Encode(net)
+}
+
+func (enc *Encoder) encodeBundle(bundle *Bundle) error {
+	var net, err = newNetwork(bundle.Network)
+	if err != nil {
+		return err
+	}
+	net.Preprocessing = bundle.Preprocessing
+	if c := bundle.Checkpoint; c != nil {
+		net.Checkpoint = &Checkpoint{
+			Epoch:    c.Epoch,
+			Schedule: c.Schedule,
+			Rand:     c.Rand,
+		}
+	}
+	return json.NewEncoder(enc.writer).Encode(net)
+}
+
+func newNetwork(network *som.Network) (net *Network, err error) {
 	if network == nil {
-		return errors.WithMessage(errors.InvalidParameterValueError, "network is nil")
+		err = errors.WithMessage(errors.InvalidParameterValueError, "network is nil")
+		return
 	}
-	var net = &Network{
+	net = &Network{
 		Features: network.Features,
 		Metrics:  network.Metrics,
 		Shape:    network.Shape,
 		Topology: network.Topology,
 		Weights:  network.Weights,
 	}
-
-	return json.NewEncoder(enc.writer).Encode(net)
+	return
 }
 
 type Decoder struct {
@@ -58,8 +99,10 @@ func (dec *Decoder) Decode(v any) (err error) {
 	switch value := v.(type) {
 	case *som.Network:
 		return dec.decode(value)
+	case *Bundle:
+		return dec.decodeBundle(value)
 	default:
-		err = errors.WithMessage(errors.InvalidParameterError, "v must be *som.Network")
+		err = errors.WithMessage(errors.InvalidParameterError, "v must be either *som.Network or *Bundle")
 		return
 	}
 }
@@ -73,13 +116,43 @@ func (dec *Decoder) decode(network *som.Network) (err error) {
 	if err = json.NewDecoder(dec.reader).Decode(net); err != nil {
 		return
 	}
+	if err = net.validate(); err != nil {
+		return
+	}
+	setNetwork(network, net)
+	return
+}
+
+func (dec *Decoder) decodeBundle(bundle *Bundle) (err error) {
+	if bundle == nil || bundle.Network == nil {
+		err = errors.WithMessage(errors.InvalidParameterValueError, "network is nil")
+		return
+	}
+	var net = new(Network)
+	if err = json.NewDecoder(dec.reader).Decode(net); err != nil {
+		return
+	}
+	if err = net.validate(); err != nil {
+		return
+	}
+	setNetwork(bundle.Network, net)
+	bundle.Preprocessing = net.Preprocessing
+	if c := net.Checkpoint; c != nil {
+		bundle.Checkpoint = &som.Checkpoint{
+			Epoch:    c.Epoch,
+			Schedule: c.Schedule,
+			Rand:     c.Rand,
+		}
+	}
+	return
+}
+
+func setNetwork(network *som.Network, net *Network) {
 	network.Features = net.Features
 	network.Shape = net.Shape
 	network.Metrics = net.Metrics
 	network.Topology = net.Topology
 	network.Weights = net.Weights
-
-	return
 }
 
 func Decode(buffer []byte, network *som.Network) (err error) {
@@ -91,11 +164,9 @@ func Decode(buffer []byte, network *som.Network) (err error) {
 	if err = json.Unmarshal(buffer, net); err != nil {
 		return
 	}
-	network.Features = net.Features
-	network.Shape = net.Shape
-	network.Metrics = net.Metrics
-	network.Topology = net.Topology
-	network.Weights = net.Weights
-
+	if err = net.validate(); err != nil {
+		return
+	}
+	setNetwork(network, net)
 	return
 }
@@ -2,10 +2,13 @@ package som
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"github.com/publiczny81/ml/ann/som"
 	"github.com/publiczny81/ml/errors"
 	"github.com/publiczny81/ml/metrics"
+	"github.com/publiczny81/ml/preprocessing"
+	"github.com/publiczny81/ml/sampling"
 	"github.com/stretchr/testify/assert"
 	"io"
 	"testing"
@@ -146,3 +149,33 @@ func TestDecoder(t *testing.T) {
 		})
 	}
 }
+
+func TestDecoderWithUnknownMetrics(t *testing.T) {
+	var (
+		buffer = []byte(`{"features":1,"metrics":"unknown","shape":[2],"topology":"linear","weights":[1,2]}`)
+		err    = NewDecoder(bytes.NewReader(buffer)).Decode(new(som.Network))
+	)
+	assert.ErrorIs(t, err, errors.UnknownNameError)
+	assert.ErrorContains(t, err, "metrics=unknown")
+
+	err = Decode(buffer, new(som.Network))
+	assert.ErrorIs(t, err, errors.UnknownNameError)
+}
+
+func TestBundleWithPreprocessing(t *testing.T) {
+	var (
+		buffer     = new(bytes.Buffer)
+		scaler     = preprocessing.NewMinMaxScaler(0, 1)
+		network, _ = som.New(1, []int{2}, som.WithWeights([]float64{0, 1}))
+		actual     = &Bundle{Network: new(som.Network)}
+		pipeline   = preprocessing.Pipeline{scaler}
+	)
+	assert.NoError(t, scaler.Fit(context.TODO(), sampling.NewSliceSource([][]float64{{10}, {20}})))
+	assert.NoError(t, NewEncoder(buffer).Encode(Bundle{Network: network, Preprocessing: pipeline}))
+	assert.NoError(t, NewDecoder(buffer).Decode(actual))
+	assert.Equal(t, pipeline, actual.Preprocessing)
+
+	var input, err = actual.Preprocessing.Transform([]float64{17})
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0.7}, input, 1e-9)
+}
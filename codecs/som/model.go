@@ -1,9 +1,33 @@
 package som
 
+import (
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/metrics"
+	"github.com/publiczny81/ml/preprocessing"
+)
+
 type Network struct {
 	Features int       `json:"features"`
 	Metrics  string    `json:"metrics"`
 	Shape    []int     `json:"shape"`
 	Topology string    `json:"topology"`
 	Weights  []float64 `json:"weights"`
+	// Checkpoint is the state of training saved together with the network
+	Checkpoint *Checkpoint `json:"checkpoint,omitempty"`
+	// Preprocessing is applied to inputs before they are passed to the network
+	Preprocessing preprocessing.Pipeline `json:"preprocessing,omitempty"`
+}
+
+type Checkpoint struct {
+	Epoch    int    `json:"epoch"`
+	Schedule []byte `json:"schedule,omitempty"`
+	Rand     []byte `json:"rand,omitempty"`
+}
+
+// validate checks whether metrics of the network is registered
+func (net *Network) validate() error {
+	if _, found := metrics.Get(net.Metrics); !found {
+		return errors.WithMessagef(errors.UnknownNameError, "metrics=%s", net.Metrics)
+	}
+	return nil
 }
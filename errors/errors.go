@@ -9,6 +9,9 @@ var (
 	InvalidSizeOfMatrixError     = errors.New("invalid size of matrix")
 	UnmatchedSizeOfMatricesError = errors.New("unmatched size of matrices")
 	ZeroDeterminantError         = errors.New("zero determinant")
+	DuplicateNameError           = errors.New("duplicate name")
+	UnknownNameError             = errors.New("unknown name")
+	StopTrainingError            = errors.New("stop training")
 )
 
 var (
@@ -17,4 +20,6 @@ var (
 	WithMessage  = errors.WithMessage
 	WithMessagef = errors.WithMessagef
 	WithStack    = errors.WithStack
+	Is           = errors.Is
+	As           = errors.As
 )
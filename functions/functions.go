@@ -3,7 +3,7 @@ package functions
 import "math"
 
 func Sigmoid(value float64) float64 {
-	return 1 / (1 + math.Exp(value))
+	return 1 / (1 + math.Exp(-value))
 }
 
 func DerivativeSigmoid(value float64) (ret float64) {
@@ -41,3 +41,143 @@ func DerivativeLinear(a float64) func(float64) float64 {
 		return a
 	}
 }
+
+func Tanh(value float64) float64 {
+	return math.Tanh(value)
+}
+
+func DerivativeTanh(value float64) float64 {
+	var t = math.Tanh(value)
+	return 1 - t*t
+}
+
+func HardTanh(value float64) float64 {
+	return math.Max(-1, math.Min(1, value))
+}
+
+func DerivativeHardTanh(value float64) float64 {
+	if value > -1 && value < 1 {
+		return 1
+	}
+	return 0
+}
+
+func Softplus(value float64) float64 {
+	return math.Max(value, 0) + math.Log1p(math.Exp(-math.Abs(value)))
+}
+
+func DerivativeSoftplus(value float64) float64 {
+	return Sigmoid(value)
+}
+
+func Softsign(value float64) float64 {
+	return value / (1 + math.Abs(value))
+}
+
+func DerivativeSoftsign(value float64) float64 {
+	var d = 1 + math.Abs(value)
+	return 1 / (d * d)
+}
+
+func ELU(alpha float64) func(float64) float64 {
+	return func(value float64) float64 {
+		if value > 0 {
+			return value
+		}
+		return alpha * math.Expm1(value)
+	}
+}
+
+func DerivativeELU(alpha float64) func(float64) float64 {
+	return func(value float64) float64 {
+		if value > 0 {
+			return 1
+		}
+		return alpha * math.Exp(value)
+	}
+}
+
+const (
+	seluAlpha = 1.6732632423543772
+	seluScale = 1.0507009873554805
+)
+
+func SELU(value float64) float64 {
+	if value > 0 {
+		return seluScale * value
+	}
+	return seluScale * seluAlpha * math.Expm1(value)
+}
+
+func DerivativeSELU(value float64) float64 {
+	if value > 0 {
+		return seluScale
+	}
+	return seluScale * seluAlpha * math.Exp(value)
+}
+
+// GELU is the exact Gaussian Error Linear Unit x*Φ(x)
+func GELU(value float64) float64 {
+	return 0.5 * value * (1 + math.Erf(value/math.Sqrt2))
+}
+
+func DerivativeGELU(value float64) float64 {
+	return 0.5*(1+math.Erf(value/math.Sqrt2)) + value*math.Exp(-0.5*value*value)/math.Sqrt(2*math.Pi)
+}
+
+const (
+	geluScale       = 0.7978845608028654 // sqrt(2/pi)
+	geluCoefficient = 0.044715
+)
+
+// GELUTanh is the approximation of GELU with hyperbolic tangent
+func GELUTanh(value float64) float64 {
+	return 0.5 * value * (1 + math.Tanh(geluScale*(value+geluCoefficient*value*value*value)))
+}
+
+func DerivativeGELUTanh(value float64) float64 {
+	var t = math.Tanh(geluScale * (value + geluCoefficient*value*value*value))
+	return 0.5*(1+t) + 0.5*value*(1-t*t)*geluScale*(1+3*geluCoefficient*value*value)
+}
+
+func Swish(beta float64) func(float64) float64 {
+	return func(value float64) float64 {
+		return value * Sigmoid(beta*value)
+	}
+}
+
+func DerivativeSwish(beta float64) func(float64) float64 {
+	return func(value float64) float64 {
+		var s = Sigmoid(beta * value)
+		return s + beta*value*s*(1-s)
+	}
+}
+
+func Mish(value float64) float64 {
+	return value * math.Tanh(Softplus(value))
+}
+
+func DerivativeMish(value float64) float64 {
+	var t = math.Tanh(Softplus(value))
+	return t + value*(1-t*t)*Sigmoid(value)
+}
+
+// HardSigmoid is piecewise linear approximation of sigmoid x/6+1/2 clipped to <0, 1>
+func HardSigmoid(value float64) float64 {
+	return math.Max(0, math.Min(1, value/6+0.5))
+}
+
+func DerivativeHardSigmoid(value float64) float64 {
+	if value > -3 && value < 3 {
+		return 1.0 / 6
+	}
+	return 0
+}
+
+func Gaussian(value float64) float64 {
+	return math.Exp(-value * value)
+}
+
+func DerivativeGaussian(value float64) float64 {
+	return -2 * value * math.Exp(-value*value)
+}
@@ -0,0 +1,163 @@
+package learning
+
+import (
+	"encoding/binary"
+	"github.com/publiczny81/ml/errors"
+	"math"
+	"sync"
+)
+
+// ThresholdMode decides how improvement of the loss is measured
+type ThresholdMode int
+
+const (
+	// Relative mode requires the loss to drop below best*(1-threshold)
+	Relative ThresholdMode = iota
+	// Absolute mode requires the loss to drop below best-threshold
+	Absolute
+)
+
+// plateauStateSize is the size of binary state of ReduceOnPlateau: rate, best, bad and cooling
+const plateauStateSize = 4 * 8
+
+const (
+	defaultPlateauFactor    = 0.1
+	defaultPlateauPatience  = 10
+	defaultPlateauThreshold = 1e-4
+)
+
+// ReduceOnPlateau cuts the learning rate by factor when observed loss does not improve for patience epochs.
+// After the cut the scheduler waits cooldown epochs before it resumes counting epochs without improvement
+type ReduceOnPlateau struct {
+	lock      sync.RWMutex
+	rate      float64
+	factor    float64
+	patience  int
+	cooldown  int
+	minimum   float64
+	threshold float64
+	mode      ThresholdMode
+	best      float64
+	// bad is the number of epochs without improvement
+	bad int
+	// cooling is the number of epochs left until the end of cooldown
+	cooling int
+}
+
+type PlateauOption func(*ReduceOnPlateau)
+
+// WithFactor sets factor by which the rate is multiplied. The default is 0.1
+func WithFactor(factor float64) PlateauOption {
+	return func(r *ReduceOnPlateau) {
+		r.factor = factor
+	}
+}
+
+// WithPatience sets number of epochs without improvement after which the rate is cut. The default is 10
+func WithPatience(patience int) PlateauOption {
+	return func(r *ReduceOnPlateau) {
+		r.patience = max(0, patience)
+	}
+}
+
+// WithCooldown sets number of epochs to wait after the cut before counting epochs without improvement
+func WithCooldown(cooldown int) PlateauOption {
+	return func(r *ReduceOnPlateau) {
+		r.cooldown = max(0, cooldown)
+	}
+}
+
+// WithMinimumRate sets lower bound of the rate
+func WithMinimumRate(minimum float64) PlateauOption {
+	return func(r *ReduceOnPlateau) {
+		r.minimum = minimum
+	}
+}
+
+// WithThreshold sets threshold of significant improvement and the way it is measured. The default is relative 1e-4
+func WithThreshold(threshold float64, mode ThresholdMode) PlateauOption {
+	return func(r *ReduceOnPlateau) {
+		r.threshold = threshold
+		r.mode = mode
+	}
+}
+
+func NewReduceOnPlateau(initial float64, opts ...PlateauOption) (r *ReduceOnPlateau) {
+	r = &ReduceOnPlateau{
+		rate:      initial,
+		factor:    defaultPlateauFactor,
+		patience:  defaultPlateauPatience,
+		threshold: defaultPlateauThreshold,
+		mode:      Relative,
+		best:      math.Inf(1),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return
+}
+
+// LearningRate returns current rate regardless of the epoch
+func (r *ReduceOnPlateau) LearningRate(int) float64 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.rate
+}
+
+// Observe records the loss observed after the epoch and cuts the rate when the loss reached plateau
+func (r *ReduceOnPlateau) Observe(_ int, loss float64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.improved(loss) {
+		r.best = loss
+		r.bad = 0
+	} else {
+		r.bad++
+	}
+	if r.cooling > 0 {
+		r.cooling--
+		r.bad = 0
+	}
+	if r.bad > r.patience {
+		r.rate = max(r.rate*r.factor, r.minimum)
+		r.cooling = r.cooldown
+		r.bad = 0
+	}
+}
+
+func (r *ReduceOnPlateau) improved(loss float64) bool {
+	if r.mode == Absolute {
+		return loss < r.best-r.threshold
+	}
+	return loss < r.best*(1-r.threshold)
+}
+
+// MarshalBinary encodes the current rate and the progress of observation, so training can be resumed
+func (r *ReduceOnPlateau) MarshalBinary() ([]byte, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var data = make([]byte, 0, plateauStateSize)
+	data = binary.BigEndian.AppendUint64(data, math.Float64bits(r.rate))
+	data = binary.BigEndian.AppendUint64(data, math.Float64bits(r.best))
+	data = binary.BigEndian.AppendUint64(data, uint64(r.bad))
+	data = binary.BigEndian.AppendUint64(data, uint64(r.cooling))
+	return data, nil
+}
+
+// UnmarshalBinary restores the state encoded with MarshalBinary. Options of the scheduler are not restored
+func (r *ReduceOnPlateau) UnmarshalBinary(data []byte) error {
+	if len(data) != plateauStateSize {
+		return errors.WithMessagef(errors.InvalidParameterValueError, "ReduceOnPlateau: len(data)=%d", len(data))
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.rate = math.Float64frombits(binary.BigEndian.Uint64(data))
+	r.best = math.Float64frombits(binary.BigEndian.Uint64(data[8:]))
+	r.bad = int(binary.BigEndian.Uint64(data[16:]))
+	r.cooling = int(binary.BigEndian.Uint64(data[24:]))
+	return nil
+}
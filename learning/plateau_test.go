@@ -0,0 +1,88 @@
+package learning
+
+import (
+	"github.com/publiczny81/ml/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReduceOnPlateau(t *testing.T) {
+	var tests = []struct {
+		Name     string
+		Schedule *ReduceOnPlateau
+		Losses   []float64
+		Expected []float64
+	}{
+		{
+			Name:     "When loss improves then rate is kept",
+			Schedule: NewReduceOnPlateau(1, WithPatience(1)),
+			Losses:   []float64{5, 4, 3, 2},
+			Expected: []float64{1, 1, 1, 1},
+		},
+		{
+			Name:     "When loss does not improve longer than patience then rate is cut",
+			Schedule: NewReduceOnPlateau(1, WithPatience(1), WithFactor(0.5)),
+			Losses:   []float64{5, 5, 5, 5, 5},
+			Expected: []float64{1, 1, 0.5, 0.5, 0.25},
+		},
+		{
+			Name:     "When cooldown is set then epochs without improvement are not counted during cooldown",
+			Schedule: NewReduceOnPlateau(1, WithPatience(0), WithFactor(0.5), WithCooldown(2)),
+			Losses:   []float64{5, 5, 5, 5, 5},
+			Expected: []float64{1, 0.5, 0.5, 0.5, 0.25},
+		},
+		{
+			Name:     "When rate reaches minimum then it is not cut any further",
+			Schedule: NewReduceOnPlateau(1, WithPatience(0), WithFactor(0.1), WithMinimumRate(0.05)),
+			Losses:   []float64{5, 5, 5, 5},
+			Expected: []float64{1, 0.1, 0.05, 0.05},
+		},
+		{
+			Name:     "When improvement is below relative threshold then it is not significant",
+			Schedule: NewReduceOnPlateau(1, WithPatience(0), WithFactor(0.5), WithThreshold(0.1, Relative)),
+			Losses:   []float64{10, 9.5, 8},
+			Expected: []float64{1, 0.5, 0.5},
+		},
+		{
+			Name:     "When improvement is below absolute threshold then it is not significant",
+			Schedule: NewReduceOnPlateau(1, WithPatience(0), WithFactor(0.5), WithThreshold(1, Absolute)),
+			Losses:   []float64{10, 9.5, 8.5},
+			Expected: []float64{1, 0.5, 0.5},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			for i, loss := range test.Losses {
+				test.Schedule.Observe(i, loss)
+				assert.InDelta(t, test.Expected[i], test.Schedule.LearningRate(i+1), 1e-12, "epoch=%d", i)
+			}
+		})
+	}
+}
+
+func TestReduceOnPlateauState(t *testing.T) {
+	var (
+		losses      = []float64{5, 4, 4, 4, 4, 3, 3, 3}
+		continuous  = NewReduceOnPlateau(1, WithPatience(1), WithFactor(0.5), WithCooldown(1))
+		interrupted = NewReduceOnPlateau(1, WithPatience(1), WithFactor(0.5), WithCooldown(1))
+		resumed     = NewReduceOnPlateau(1, WithPatience(1), WithFactor(0.5), WithCooldown(1))
+	)
+	for i, loss := range losses {
+		continuous.Observe(i, loss)
+	}
+	for i, loss := range losses[:4] {
+		interrupted.Observe(i, loss)
+	}
+	data, err := interrupted.MarshalBinary()
+	assert.NoError(t, err)
+	assert.NoError(t, resumed.UnmarshalBinary(data))
+	for i, loss := range losses[4:] {
+		resumed.Observe(i+4, loss)
+	}
+	assert.Equal(t, continuous.LearningRate(0), resumed.LearningRate(0))
+
+	expected, _ := continuous.MarshalBinary()
+	actual, _ := resumed.MarshalBinary()
+	assert.Equal(t, expected, actual)
+	assert.ErrorIs(t, resumed.UnmarshalBinary([]byte{1}), errors.InvalidParameterValueError)
+}
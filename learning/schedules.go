@@ -0,0 +1,133 @@
+package learning
+
+import "math"
+
+// Schedule defines contract for learning rate schedules consumed by trainers
+type Schedule interface {
+	LearningRate(epoch int) float64
+}
+
+// StepDecay multiplies initial rate by factor every step epochs
+func StepDecay(initial, factor float64, step int) Scheduler {
+	step = max(1, step)
+	return func(epoch int) float64 {
+		return initial * math.Pow(factor, float64(epoch/step))
+	}
+}
+
+// ExponentialDecay multiplies initial rate by decay every epoch
+func ExponentialDecay(initial, decay float64) Scheduler {
+	return func(epoch int) float64 {
+		return initial * math.Pow(decay, float64(epoch))
+	}
+}
+
+// InverseTimeDecay divides initial rate by 1+decay*epoch
+func InverseTimeDecay(initial, decay float64) Scheduler {
+	return func(epoch int) float64 {
+		return initial / (1 + decay*float64(epoch))
+	}
+}
+
+// PolynomialDecay decays initial rate to final one within epochs following polynomial of given power.
+// The rate stays final after epochs
+func PolynomialDecay(initial, final float64, epochs int, power float64) Scheduler {
+	epochs = max(1, epochs)
+	return func(epoch int) float64 {
+		var progress = float64(min(max(0, epoch), epochs)) / float64(epochs)
+		return (initial-final)*math.Pow(1-progress, power) + final
+	}
+}
+
+// CosineWarmRestarts anneals the rate from maximum to minimum following cosine and restarts it every period (SGDR).
+// Each following period is multiplier times longer than the previous one
+func CosineWarmRestarts(maximum, minimum float64, period, multiplier int) Scheduler {
+	period, multiplier = max(1, period), max(1, multiplier)
+	return func(epoch int) float64 {
+		var current, length = max(0, epoch), period
+		for current >= length {
+			current -= length
+			length *= multiplier
+		}
+		return cosine(maximum, minimum, float64(current)/float64(length))
+	}
+}
+
+// Triangular cycles the rate linearly between minimum and maximum. The rate reaches maximum after halfCycle
+// epochs and returns to minimum after the next halfCycle epochs
+func Triangular(minimum, maximum float64, halfCycle int) Scheduler {
+	halfCycle = max(1, halfCycle)
+	return func(epoch int) float64 {
+		var position = max(0, epoch) % (2 * halfCycle)
+		if position > halfCycle {
+			position = 2*halfCycle - position
+		}
+		return minimum + (maximum-minimum)*float64(position)/float64(halfCycle)
+	}
+}
+
+// OneCycle increases the rate linearly from initial to maximum until peak epoch and then anneals it
+// following cosine to final until epochs. The rate stays final after epochs
+func OneCycle(initial, maximum, final float64, peak, epochs int) Scheduler {
+	peak = max(0, peak)
+	epochs = max(peak+1, epochs)
+	return func(epoch int) float64 {
+		switch {
+		case epoch < peak:
+			return initial + (maximum-initial)*float64(max(0, epoch))/float64(peak)
+		case epoch < epochs:
+			return cosine(maximum, final, float64(epoch-peak)/float64(epochs-peak))
+		default:
+			return final
+		}
+	}
+}
+
+// Warmup increases the rate linearly from zero to the rate of schedule within epochs
+func Warmup(schedule Schedule, epochs int) Scheduler {
+	return func(epoch int) float64 {
+		var rate = schedule.LearningRate(epoch)
+		if epoch < epochs {
+			return rate * float64(max(0, epoch)) / float64(epochs)
+		}
+		return rate
+	}
+}
+
+// Stage is a schedule applied for given number of epochs
+type Stage struct {
+	Schedule Schedule
+	Epochs   int
+}
+
+// Chain applies stages one after another. Each stage counts epochs from zero. The last stage lasts forever
+func Chain(stages ...Stage) Scheduler {
+	return func(epoch int) float64 {
+		if len(stages) == 0 {
+			return 0
+		}
+		for _, stage := range stages[:len(stages)-1] {
+			if epoch < stage.Epochs {
+				return stage.Schedule.LearningRate(epoch)
+			}
+			epoch -= stage.Epochs
+		}
+		return stages[len(stages)-1].Schedule.LearningRate(epoch)
+	}
+}
+
+// Compose multiplies rates of schedules, e.g. Compose(ConstantRate(0.1), Triangular(0.5, 1, 5))
+func Compose(schedules ...Schedule) Scheduler {
+	return func(epoch int) float64 {
+		var rate = 1.0
+		for _, s := range schedules {
+			rate *= s.LearningRate(epoch)
+		}
+		return rate
+	}
+}
+
+// cosine anneals from maximum to minimum as progress goes from 0 to 1
+func cosine(maximum, minimum, progress float64) float64 {
+	return minimum + (maximum-minimum)*(1+math.Cos(math.Pi*progress))/2
+}
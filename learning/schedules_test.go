@@ -0,0 +1,80 @@
+package learning
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSchedules(t *testing.T) {
+	var tests = []struct {
+		Name     string
+		Rate     Scheduler
+		Expected map[int]float64
+	}{
+		{
+			Name:     "When step decay is used then rate drops every step epochs",
+			Rate:     StepDecay(1, 0.5, 2),
+			Expected: map[int]float64{0: 1, 1: 1, 2: 0.5, 5: 0.25},
+		},
+		{
+			Name:     "When exponential decay is used then rate is multiplied by decay every epoch",
+			Rate:     ExponentialDecay(2, 0.5),
+			Expected: map[int]float64{0: 2, 1: 1, 3: 0.25},
+		},
+		{
+			Name:     "When inverse time decay is used then rate is divided by linear function of epoch",
+			Rate:     InverseTimeDecay(1, 0.5),
+			Expected: map[int]float64{0: 1, 2: 0.5, 6: 0.25},
+		},
+		{
+			Name:     "When polynomial decay is used then rate reaches final rate after epochs",
+			Rate:     PolynomialDecay(1, 0.1, 4, 2),
+			Expected: map[int]float64{0: 1, 2: 0.325, 4: 0.1, 10: 0.1},
+		},
+		{
+			Name:     "When cosine warm restarts are used then rate restarts after each period",
+			Rate:     CosineWarmRestarts(1, 0, 2, 2),
+			Expected: map[int]float64{0: 1, 1: 0.5, 2: 1, 3: 0.8535533905932737, 4: 0.5, 6: 1},
+		},
+		{
+			Name:     "When triangular schedule is used then rate cycles between minimum and maximum",
+			Rate:     Triangular(0.1, 0.5, 2),
+			Expected: map[int]float64{0: 0.1, 1: 0.3, 2: 0.5, 3: 0.3, 4: 0.1, 6: 0.5},
+		},
+		{
+			Name:     "When one cycle schedule is used then rate rises to maximum and anneals to final rate",
+			Rate:     OneCycle(0.1, 1, 0.01, 2, 4),
+			Expected: map[int]float64{0: 0.1, 1: 0.55, 2: 1, 3: 0.505, 4: 0.01, 8: 0.01},
+		},
+		{
+			Name:     "When warmup is used then rate grows linearly to rate of schedule",
+			Rate:     Warmup(ConstantRate(0.4), 4),
+			Expected: map[int]float64{0: 0, 1: 0.1, 2: 0.2, 4: 0.4, 9: 0.4},
+		},
+		{
+			Name: "When schedules are chained then each of them counts epochs from zero",
+			Rate: Chain(
+				Stage{Schedule: Warmup(ConstantRate(1), 2), Epochs: 2},
+				Stage{Schedule: StepDecay(1, 0.5, 1), Epochs: 2},
+				Stage{Schedule: ConstantRate(0.1)},
+			),
+			Expected: map[int]float64{0: 0, 1: 0.5, 2: 1, 3: 0.5, 4: 0.1, 100: 0.1},
+		},
+		{
+			Name:     "When schedules are composed then their rates are multiplied",
+			Rate:     Compose(ConstantRate(0.1), Triangular(0.5, 1, 1)),
+			Expected: map[int]float64{0: 0.05, 1: 0.1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			for epoch, expected := range test.Expected {
+				assert.InDelta(t, expected, test.Rate.LearningRate(epoch), 1e-12, "epoch=%d", epoch)
+			}
+		})
+	}
+}
+
+func TestChainWithoutStages(t *testing.T) {
+	assert.Equal(t, 0.0, Chain().LearningRate(1))
+}
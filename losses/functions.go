@@ -3,8 +3,14 @@ package losses
 import (
 	"github.com/publiczny81/ml/calculus/types"
 	"github.com/publiczny81/ml/calculus/vector"
+	"github.com/publiczny81/ml/calculus/vector/pool"
+	"github.com/publiczny81/ml/errors"
+	"math"
 )
 
+// epsilon keeps probabilities away from 0 and 1 before taking logarithms
+const epsilon = 1e-7
+
 func MeanSquareError[T types.Float](actual []T, predicted []T) (partials []T, value T) {
 	if len(actual) == 0 {
 		return
@@ -13,3 +19,266 @@ func MeanSquareError[T types.Float](actual []T, predicted []T) (partials []T, va
 	value = vector.DotProduct(partials, partials) / T(len(actual))
 	return
 }
+
+func MeanAbsoluteError[T types.Float](actual []T, predicted []T) (partials []T, value T) {
+	return mean(actual, predicted, func(a, p T) (T, T) {
+		var r = a - p
+		switch {
+		case r > 0:
+			return 1, r
+		case r < 0:
+			return -1, -r
+		default:
+			return 0, 0
+		}
+	})
+}
+
+// HuberLoss is quadratic for residuals smaller than delta and linear otherwise
+func HuberLoss[T types.Float](delta T) func(actual []T, predicted []T) (partials []T, value T) {
+	return func(actual []T, predicted []T) (partials []T, value T) {
+		return mean(actual, predicted, func(a, p T) (T, T) {
+			var r = a - p
+			if T(math.Abs(float64(r))) <= delta {
+				return r, r * r / 2
+			}
+			return delta * sign(r), delta * (T(math.Abs(float64(r))) - delta/2)
+		})
+	}
+}
+
+// SmoothL1Loss is Huber loss divided by beta
+func SmoothL1Loss[T types.Float](beta T) func(actual []T, predicted []T) (partials []T, value T) {
+	return func(actual []T, predicted []T) (partials []T, value T) {
+		return mean(actual, predicted, func(a, p T) (T, T) {
+			var r = a - p
+			if T(math.Abs(float64(r))) < beta {
+				return r / beta, r * r / (2 * beta)
+			}
+			return sign(r), T(math.Abs(float64(r))) - beta/2
+		})
+	}
+}
+
+func LogCoshLoss[T types.Float](actual []T, predicted []T) (partials []T, value T) {
+	return mean(actual, predicted, func(a, p T) (T, T) {
+		var x = math.Abs(float64(p - a))
+		// log(cosh(x)) computed without overflow
+		return T(math.Tanh(float64(a - p))), T(x + math.Log1p(math.Exp(-2*x)) - math.Ln2)
+	})
+}
+
+// BinaryCrossEntropyLoss expects predicted probabilities and actual labels in range <0, 1>
+func BinaryCrossEntropyLoss[T types.Float](actual []T, predicted []T) (partials []T, value T) {
+	return mean(actual, predicted, func(a, p T) (T, T) {
+		p = clip(p)
+		return a/p - (1-a)/(1-p), -(a*T(math.Log(float64(p))) + (1-a)*T(math.Log(float64(1-p))))
+	})
+}
+
+// FocalLoss is binary cross entropy which down-weights well classified samples with gamma and balances
+// positive and negative labels with alpha
+func FocalLoss[T types.Float](gamma, alpha T) func(actual []T, predicted []T) (partials []T, value T) {
+	return func(actual []T, predicted []T) (partials []T, value T) {
+		return mean(actual, predicted, func(a, p T) (T, T) {
+			p = clip(p)
+			var (
+				g        = float64(gamma)
+				pf       = float64(p)
+				logP     = math.Log(pf)
+				log1P    = math.Log(1 - pf)
+				positive = float64(alpha*a) * math.Pow(1-pf, g)
+				negative = float64((1-alpha)*(1-a)) * math.Pow(pf, g)
+				dPos     = float64(alpha*a) * (-g*math.Pow(1-pf, g-1)*logP + math.Pow(1-pf, g)/pf)
+				dNeg     = float64((1-alpha)*(1-a)) * (g*math.Pow(pf, g-1)*log1P - math.Pow(pf, g)/(1-pf))
+			)
+			return T(dPos + dNeg), T(-positive*logP - negative*log1P)
+		})
+	}
+}
+
+// HingeLoss expects actual labels -1 or 1
+func HingeLoss[T types.Float](actual []T, predicted []T) (partials []T, value T) {
+	return mean(actual, predicted, func(a, p T) (T, T) {
+		if margin := 1 - a*p; margin > 0 {
+			return a, margin
+		}
+		return 0, 0
+	})
+}
+
+// SquaredHingeLoss expects actual labels -1 or 1
+func SquaredHingeLoss[T types.Float](actual []T, predicted []T) (partials []T, value T) {
+	return mean(actual, predicted, func(a, p T) (T, T) {
+		if margin := 1 - a*p; margin > 0 {
+			return 2 * a * margin, margin * margin
+		}
+		return 0, 0
+	})
+}
+
+// PoissonLoss expects positive predicted rates
+func PoissonLoss[T types.Float](actual []T, predicted []T) (partials []T, value T) {
+	return mean(actual, predicted, func(a, p T) (T, T) {
+		p = max(p, epsilon)
+		return a/p - 1, p - a*T(math.Log(float64(p)))
+	})
+}
+
+// CategoricalCrossEntropyLoss expects predicted probabilities, e.g. output of softmax, and actual distribution
+// of classes. The value is summed over classes
+func CategoricalCrossEntropyLoss[T types.Float](actual []T, predicted []T) (partials []T, value T) {
+	return categoricalCrossEntropy(actual, predicted, nil)
+}
+
+// CategoricalCrossEntropyWithClassWeights returns categorical cross entropy which scales contribution of each class
+// by its weight. Classes missing in the map have weight 1
+func CategoricalCrossEntropyWithClassWeights[T types.Float](classWeights map[int]T) Func[T] {
+	return func(actual []T, predicted []T) (partials []T, value T) {
+		return categoricalCrossEntropy(actual, predicted, classWeights)
+	}
+}
+
+func categoricalCrossEntropy[T types.Float](actual []T, predicted []T, classWeights map[int]T) (partials []T, value T) {
+	if partials, value = sum(actual, predicted, func(a, p T) (T, T) {
+		p = clip(p)
+		return a / p, -a * T(math.Log(float64(p)))
+	}); len(classWeights) == 0 {
+		return
+	}
+	value = 0
+	for i := range partials {
+		var w = classWeight(classWeights, i)
+		partials[i] *= w
+		value -= w * actual[i] * T(math.Log(float64(clip(predicted[i]))))
+	}
+	return
+}
+
+// KLDivergenceLoss is Kullback-Leibler divergence of predicted distribution from actual one
+func KLDivergenceLoss[T types.Float](actual []T, predicted []T) (partials []T, value T) {
+	return sum(actual, predicted, func(a, p T) (T, T) {
+		if a <= 0 {
+			return 0, 0
+		}
+		p = clip(p)
+		return a / p, a * T(math.Log(float64(a/p)))
+	})
+}
+
+// SoftmaxCrossEntropyLoss fuses softmax with categorical cross entropy. It expects logits as predicted values
+// and computes the value and partials with respect to logits without overflow
+func SoftmaxCrossEntropyLoss[T types.Float](actual []T, predicted []T) (partials []T, value T) {
+	return softmaxCrossEntropy(actual, predicted, nil)
+}
+
+// SoftmaxCrossEntropyWithClassWeights returns softmax fused with categorical cross entropy which scales
+// contribution of each class by its weight. Classes missing in the map have weight 1
+func SoftmaxCrossEntropyWithClassWeights[T types.Float](classWeights map[int]T) Func[T] {
+	return func(actual []T, predicted []T) (partials []T, value T) {
+		return softmaxCrossEntropy(actual, predicted, classWeights)
+	}
+}
+
+func softmaxCrossEntropy[T types.Float](actual []T, predicted []T, classWeights map[int]T) (partials []T, value T) {
+	if len(actual) != len(predicted) {
+		panic(errors.UnmatchedSizeOfVectorsError)
+	}
+	if len(actual) == 0 {
+		return
+	}
+	var (
+		m     = predicted[0]
+		total float64
+		mass  T
+	)
+	for _, z := range predicted {
+		m = max(m, z)
+	}
+	for i, z := range predicted {
+		total += math.Exp(float64(z - m))
+		mass += classWeight(classWeights, i) * actual[i]
+	}
+	var lse = m + T(math.Log(total))
+
+	partials = pool.Get[[]T](len(actual))
+	for i, z := range predicted {
+		var w = classWeight(classWeights, i)
+		partials[i] = w*actual[i] - T(math.Exp(float64(z-lse)))*mass
+		value += w * actual[i] * (lse - z)
+	}
+	return
+}
+
+// CosineSimilarityLoss is one minus cosine similarity of actual and predicted vectors
+func CosineSimilarityLoss[T types.Float](actual []T, predicted []T) (partials []T, value T) {
+	if len(actual) != len(predicted) {
+		panic(errors.UnmatchedSizeOfVectorsError)
+	}
+	if len(actual) == 0 {
+		return
+	}
+	var (
+		na     = T(math.Sqrt(float64(vector.DotProduct(actual, actual))))
+		np     = T(math.Sqrt(float64(vector.DotProduct(predicted, predicted))))
+		cosine T
+	)
+	partials = pool.Get[[]T](len(actual))
+	if na == 0 || np == 0 {
+		value = 1
+		return
+	}
+	cosine = vector.DotProduct(actual, predicted) / (na * np)
+	for i := range partials {
+		partials[i] = actual[i]/(na*np) - cosine*predicted[i]/(np*np)
+	}
+	value = 1 - cosine
+	return
+}
+
+// mean applies f to each pair of actual and predicted values and returns partials and mean of values
+func mean[T types.Float](actual []T, predicted []T, f func(a, p T) (partial, value T)) (partials []T, value T) {
+	if partials, value = sum(actual, predicted, f); len(actual) > 0 {
+		value /= T(len(actual))
+	}
+	return
+}
+
+// sum applies f to each pair of actual and predicted values and returns partials and sum of values
+func sum[T types.Float](actual []T, predicted []T, f func(a, p T) (partial, value T)) (partials []T, value T) {
+	if len(actual) != len(predicted) {
+		panic(errors.UnmatchedSizeOfVectorsError)
+	}
+	if len(actual) == 0 {
+		return
+	}
+	partials = pool.Get[[]T](len(actual))
+	for i, a := range actual {
+		var v T
+		partials[i], v = f(a, predicted[i])
+		value += v
+	}
+	return
+}
+
+func classWeight[T types.Float](classWeights map[int]T, class int) T {
+	if w, found := classWeights[class]; found {
+		return w
+	}
+	return 1
+}
+
+func clip[T types.Float](p T) T {
+	return min(max(p, epsilon), 1-epsilon)
+}
+
+func sign[T types.Float](x T) T {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	default:
+		return 0
+	}
+}
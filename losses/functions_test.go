@@ -1,7 +1,9 @@
 package losses
 
 import (
+	"github.com/publiczny81/ml/errors"
 	"github.com/stretchr/testify/assert"
+	"math"
 	"testing"
 )
 
@@ -56,3 +58,140 @@ func TestMeanSquareError(t *testing.T) {
 		})
 	}
 }
+
+// unregister removes the loss registered by the test, so tests can be run repeatedly
+func unregister(t *testing.T, name string) {
+	t.Cleanup(func() {
+		registerLock.Lock()
+		defer registerLock.Unlock()
+		delete(register, name)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	var absolute = Loss{
+		Function: func(actual, predicted []float64) (partials []float64, value float64) {
+			for i := range actual {
+				partials = append(partials, actual[i]-predicted[i])
+				value += math.Abs(actual[i] - predicted[i])
+			}
+			return
+		},
+	}
+	unregister(t, "custom_absolute")
+	assert.NoError(t, Register("custom_absolute", absolute))
+	assert.ErrorIs(t, Register("custom_absolute", absolute), errors.DuplicateNameError)
+	assert.ErrorIs(t, Register(MeanSquare, absolute), errors.DuplicateNameError)
+	assert.ErrorIs(t, Register("", absolute), errors.InvalidParameterValueError)
+	assert.ErrorIs(t, Register("nil", Loss{}), errors.InvalidParameterError)
+	assert.ErrorIs(t, Register("custom@1", absolute), errors.InvalidParameterValueError)
+
+	var l, err = Get("custom_absolute")
+	assert.NoError(t, err)
+	assert.Equal(t, "custom_absolute", l.Name)
+	_, value := l.Function([]float64{1, 2}, []float64{2, 4})
+	assert.Equal(t, 3.0, value)
+
+	l, err = Get(MeanSquare)
+	assert.NoError(t, err)
+	assert.Equal(t, MeanSquare, l.Name)
+}
+
+func TestLosses(t *testing.T) {
+	const h = 1e-6
+	var (
+		probabilities = []float64{0.2, 0.7, 0.1}
+		distribution  = []float64{0, 1, 0}
+		tests         = []struct {
+			Name      string
+			Actual    []float64
+			Predicted []float64
+			Value     float64
+			// Mean is true when the value is averaged over outputs
+			Mean bool
+		}{
+			{Name: MeanAbsolute, Actual: []float64{1, 2, 3}, Predicted: []float64{2, 2, 1}, Value: 1, Mean: true},
+			{Name: Huber, Actual: []float64{1, 2, 3}, Predicted: []float64{1.5, 2, 0.5}, Value: (0.125 + 2) / 3, Mean: true},
+			{Name: SmoothL1, Actual: []float64{1, 2, 3}, Predicted: []float64{1.5, 2, 0.5}, Value: (0.125 + 2) / 3, Mean: true},
+			{Name: "huber@0.5", Actual: []float64{1, 2, 3}, Predicted: []float64{1.5, 2, 0.5}, Value: (0.125 + 1.125) / 3, Mean: true},
+			{Name: "smooth_l1@2", Actual: []float64{1, 2, 3}, Predicted: []float64{1.5, 2, 0.5}, Value: (0.0625 + 1.5) / 3, Mean: true},
+			{Name: LogCosh, Actual: []float64{1, 2}, Predicted: []float64{1.5, -30}, Value: (math.Log(math.Cosh(0.5)) + 32 - math.Ln2) / 2, Mean: true},
+			{Name: BinaryCrossEntropy, Actual: []float64{1, 0, 1}, Predicted: probabilities, Value: -(math.Log(0.2) + math.Log(0.3) + math.Log(0.1)) / 3, Mean: true},
+			{Name: Focal, Actual: []float64{1, 0, 1}, Predicted: probabilities, Value: -(0.25*0.64*math.Log(0.2) + 0.75*0.49*math.Log(0.3) + 0.25*0.81*math.Log(0.1)) / 3, Mean: true},
+			{Name: "focal@1@0.5", Actual: []float64{1, 0, 1}, Predicted: probabilities, Value: -(0.5*0.8*math.Log(0.2) + 0.5*0.7*math.Log(0.3) + 0.5*0.9*math.Log(0.1)) / 3, Mean: true},
+			{Name: "focal@0", Actual: []float64{1, 0, 1}, Predicted: probabilities, Value: -(0.25*math.Log(0.2) + 0.75*math.Log(0.3) + 0.25*math.Log(0.1)) / 3, Mean: true},
+			{Name: Hinge, Actual: []float64{1, -1, 1}, Predicted: []float64{0.5, 0.5, 2}, Value: 2.0 / 3, Mean: true},
+			{Name: SquaredHinge, Actual: []float64{1, -1, 1}, Predicted: []float64{0.5, 0.5, 2}, Value: 2.5 / 3, Mean: true},
+			{Name: Poisson, Actual: []float64{1, 2}, Predicted: []float64{2, 1}, Value: (2 - math.Log(2) + 1) / 2, Mean: true},
+			{Name: CategoricalCrossEntropy, Actual: distribution, Predicted: probabilities, Value: -math.Log(0.7)},
+			{Name: KLDivergence, Actual: []float64{0.5, 0.5, 0}, Predicted: probabilities, Value: 0.5*math.Log(0.5/0.2) + 0.5*math.Log(0.5/0.7)},
+			{Name: SoftmaxCrossEntropy, Actual: distribution, Predicted: []float64{1, 2, 3}, Value: 1.4076059644443801},
+			{Name: CosineSimilarity, Actual: []float64{1, 0}, Predicted: []float64{1, 1}, Value: 1 - math.Sqrt2/2},
+		}
+	)
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var l, err = Get(test.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, test.Name, l.Name)
+
+			var partials, value = l.Function(test.Actual, test.Predicted)
+			assert.InDelta(t, test.Value, value, 1e-9)
+			assert.Len(t, partials, len(test.Actual))
+
+			var scale = 1.0
+			if test.Mean {
+				scale = float64(len(test.Actual))
+			}
+			for i := range test.Predicted {
+				var (
+					plus  = append([]float64(nil), test.Predicted...)
+					minus = append([]float64(nil), test.Predicted...)
+				)
+				plus[i] += h
+				minus[i] -= h
+				var (
+					_, upper = l.Function(test.Actual, plus)
+					_, lower = l.Function(test.Actual, minus)
+				)
+				assert.InDelta(t, -scale*(upper-lower)/(2*h), partials[i], 1e-5, "partials[%d]", i)
+			}
+		})
+	}
+}
+
+func TestGetWithInvalidParameters(t *testing.T) {
+	for _, name := range []string{"huber@x", "huber@0", "huber@1@2", "smooth_l1@-1", "focal@-1", "focal@2@1.5"} {
+		t.Run(name, func(t *testing.T) {
+			var _, err = Get(name)
+			assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+		})
+	}
+	for _, name := range []string{"unknown", "mse@1", "unknown@1"} {
+		t.Run(name, func(t *testing.T) {
+			var _, err = Get(name)
+			assert.ErrorIs(t, err, errors.UnknownNameError)
+		})
+	}
+}
+
+func TestSoftmaxCrossEntropyIsStable(t *testing.T) {
+	var partials, value = SoftmaxCrossEntropyLoss([]float64{1, 0}, []float64{1000, -1000})
+	assert.Equal(t, 0.0, value)
+	assert.Equal(t, []float64{0, 0}, partials)
+
+	partials, value = SoftmaxCrossEntropyLoss([]float64{0, 1}, []float64{1000, -1000})
+	assert.Equal(t, 2000.0, value)
+	assert.Equal(t, []float64{-1, 1}, partials)
+}
+
+func TestLossesWithUnmatchedSizes(t *testing.T) {
+	for _, name := range []string{MeanAbsolute, CategoricalCrossEntropy, SoftmaxCrossEntropy, CosineSimilarity} {
+		t.Run(name, func(t *testing.T) {
+			var l, _ = Get(name)
+			assert.PanicsWithError(t, errors.UnmatchedSizeOfVectorsError.Error(), func() {
+				_, _ = l.Function([]float64{1, 2}, []float64{1})
+			})
+		})
+	}
+}
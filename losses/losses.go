@@ -0,0 +1,150 @@
+package losses
+
+import (
+	"github.com/publiczny81/ml/errors"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	MeanSquare              = "mse"
+	MeanAbsolute            = "mae"
+	Huber                   = "huber"
+	SmoothL1                = "smooth_l1"
+	LogCosh                 = "logcosh"
+	BinaryCrossEntropy      = "binary_crossentropy"
+	Focal                   = "focal"
+	Hinge                   = "hinge"
+	SquaredHinge            = "squared_hinge"
+	Poisson                 = "poisson"
+	CategoricalCrossEntropy = "categorical_crossentropy"
+	SoftmaxCrossEntropy     = "softmax_crossentropy"
+	KLDivergence            = "kl_divergence"
+	CosineSimilarity        = "cosine_similarity"
+)
+
+const (
+	defaultHuberDelta   = 1.0
+	defaultSmoothL1Beta = 1.0
+	defaultFocalGamma   = 2.0
+	defaultFocalAlpha   = 0.25
+)
+
+const (
+	separator = "@"
+)
+
+// Loss represents named loss function. Function returns partials and the value of the loss.
+// Partials are negative derivatives of the loss of each output with respect to predicted values, so they point
+// in the direction which decreases the loss. Mean square error keeps its historical partials actual-predicted
+// which correspond to the half of the squared error
+type Loss struct {
+	Name     string
+	Function func(actual, predicted []float64) (partials []float64, value float64)
+}
+
+var (
+	registerLock sync.RWMutex
+	register     = map[string]Loss{
+		MeanSquare:              {Name: MeanSquare, Function: MeanSquareError[float64]},
+		MeanAbsolute:            {Name: MeanAbsolute, Function: MeanAbsoluteError[float64]},
+		Huber:                   {Name: Huber, Function: HuberLoss[float64](defaultHuberDelta)},
+		SmoothL1:                {Name: SmoothL1, Function: SmoothL1Loss[float64](defaultSmoothL1Beta)},
+		LogCosh:                 {Name: LogCosh, Function: LogCoshLoss[float64]},
+		BinaryCrossEntropy:      {Name: BinaryCrossEntropy, Function: BinaryCrossEntropyLoss[float64]},
+		Focal:                   {Name: Focal, Function: FocalLoss[float64](defaultFocalGamma, defaultFocalAlpha)},
+		Hinge:                   {Name: Hinge, Function: HingeLoss[float64]},
+		SquaredHinge:            {Name: SquaredHinge, Function: SquaredHingeLoss[float64]},
+		Poisson:                 {Name: Poisson, Function: PoissonLoss[float64]},
+		CategoricalCrossEntropy: {Name: CategoricalCrossEntropy, Function: CategoricalCrossEntropyLoss[float64]},
+		SoftmaxCrossEntropy:     {Name: SoftmaxCrossEntropy, Function: SoftmaxCrossEntropyLoss[float64]},
+		KLDivergence:            {Name: KLDivergence, Function: KLDivergenceLoss[float64]},
+		CosineSimilarity:        {Name: CosineSimilarity, Function: CosineSimilarityLoss[float64]},
+	}
+	// parametric creates functions of losses which accept parameters after separator in the name.
+	// The factory reports false when parameters are out of range
+	parametric = map[string]struct {
+		defaults []float64
+		factory  func(params []float64) (func(actual, predicted []float64) ([]float64, float64), bool)
+	}{
+		Huber: {
+			defaults: []float64{defaultHuberDelta},
+			factory: func(params []float64) (func(actual, predicted []float64) ([]float64, float64), bool) {
+				return HuberLoss(params[0]), params[0] > 0
+			},
+		},
+		SmoothL1: {
+			defaults: []float64{defaultSmoothL1Beta},
+			factory: func(params []float64) (func(actual, predicted []float64) ([]float64, float64), bool) {
+				return SmoothL1Loss(params[0]), params[0] > 0
+			},
+		},
+		Focal: {
+			defaults: []float64{defaultFocalGamma, defaultFocalAlpha},
+			factory: func(params []float64) (func(actual, predicted []float64) ([]float64, float64), bool) {
+				return FocalLoss(params[0], params[1]), params[0] >= 0 && params[1] >= 0 && params[1] <= 1
+			},
+		},
+	}
+)
+
+// Register adds custom loss under given name, so it can be used by trainers and model files.
+// The name must not contain the parameter separator and must not be registered yet
+func Register(name string, l Loss) error {
+	if name == "" || strings.Contains(name, separator) {
+		return errors.WithMessagef(errors.InvalidParameterValueError, "losses.Register: name=%q", name)
+	}
+	if l.Function == nil {
+		return errors.WithMessagef(errors.InvalidParameterError, "losses.Register: function of %s is nil", name)
+	}
+	registerLock.Lock()
+	defer registerLock.Unlock()
+
+	if _, found := register[name]; found {
+		return errors.WithMessagef(errors.DuplicateNameError, "losses.Register: %s", name)
+	}
+	l.Name = name
+	register[name] = l
+	return nil
+}
+
+// Get returns loss registered under the name. Parameters of Huber, SmoothL1 and Focal losses may be given after
+// separator in the name, e.g. "huber@0.5" or "focal@2@0.5", and omitted trailing parameters take default values.
+// It returns error wrapping errors.UnknownNameError if the name is not registered and errors.InvalidParameterValueError
+// if parameters are malformed or out of range
+func Get(name string) (l Loss, err error) {
+	var names = strings.Split(name, separator)
+	if len(names) == 1 {
+		var found bool
+		registerLock.RLock()
+		l, found = register[name]
+		registerLock.RUnlock()
+		if !found {
+			err = errors.WithMessagef(errors.UnknownNameError, "losses.Get: name=%s", name)
+		}
+		return
+	}
+	var p, found = parametric[names[0]]
+	if !found {
+		err = errors.WithMessagef(errors.UnknownNameError, "losses.Get: name=%s", name)
+		return
+	}
+	if len(names)-1 > len(p.defaults) {
+		err = errors.WithMessagef(errors.InvalidParameterValueError, "losses.Get: name=%s", name)
+		return
+	}
+	var params = append([]float64(nil), p.defaults...)
+	for i, sub := range names[1:] {
+		if params[i], err = strconv.ParseFloat(sub, 64); err != nil {
+			err = errors.WithMessagef(errors.InvalidParameterValueError, "losses.Get: name=%s", name)
+			return
+		}
+	}
+	var valid bool
+	if l.Function, valid = p.factory(params); !valid {
+		return Loss{}, errors.WithMessagef(errors.InvalidParameterValueError, "losses.Get: name=%s", name)
+	}
+	l.Name = name
+	return
+}
@@ -0,0 +1,50 @@
+package losses
+
+import (
+	"github.com/publiczny81/ml/calculus/types"
+	"github.com/publiczny81/ml/calculus/vector/pool"
+	"github.com/publiczny81/ml/errors"
+)
+
+// Func is a loss function which returns partials and value of the loss between actual and predicted values
+type Func[T types.Float] func(actual []T, predicted []T) (partials []T, value T)
+
+// WithOutputWeights returns loss function which scales contribution of each output by its weight.
+// It is meant for losses averaged over outputs, e.g. MeanSquareError, MeanAbsoluteError or BinaryCrossEntropyLoss.
+// Categorical losses are weighted with class weights instead
+func WithOutputWeights[T types.Float](f Func[T], weights []T) Func[T] {
+	return func(actual []T, predicted []T) (partials []T, value T) {
+		if len(actual) != len(predicted) || len(actual) != len(weights) {
+			panic(errors.UnmatchedSizeOfVectorsError)
+		}
+		if len(actual) == 0 {
+			return
+		}
+		partials = pool.Get[[]T](len(actual))
+		for i, w := range weights {
+			var p, v = f(actual[i:i+1], predicted[i:i+1])
+			partials[i] = w * p[0]
+			value += w * v
+			pool.Put(p)
+		}
+		value /= T(len(actual))
+		return
+	}
+}
+
+// WithSampleWeight returns loss function which scales partials and value of the loss by the weight of the sample
+func WithSampleWeight[T types.Float](f Func[T], weight T) Func[T] {
+	return func(actual []T, predicted []T) (partials []T, value T) {
+		partials, value = f(actual, predicted)
+		Scale(partials, weight)
+		value *= weight
+		return
+	}
+}
+
+// Scale multiplies partials by the weight in place
+func Scale[T types.Float](partials []T, weight T) {
+	for i := range partials {
+		partials[i] *= weight
+	}
+}
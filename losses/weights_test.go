@@ -0,0 +1,109 @@
+package losses
+
+import (
+	"github.com/publiczny81/ml/errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestWeightedLosses(t *testing.T) {
+	const h = 1e-6
+	var (
+		probabilities = []float64{0.2, 0.7, 0.1}
+		tests         = []struct {
+			Name      string
+			Function  Func[float64]
+			Actual    []float64
+			Predicted []float64
+			Value     float64
+			// Mean is true when the value is averaged over outputs
+			Mean bool
+		}{
+			{
+				Name:      "When outputs are weighted then their errors are scaled",
+				Function:  WithOutputWeights(MeanAbsoluteError[float64], []float64{2, 0, 1}),
+				Actual:    []float64{1, 2, 3},
+				Predicted: []float64{2, 5, 1},
+				Value:     (2*1 + 1*2) / 3.0,
+				Mean:      true,
+			},
+			{
+				Name:      "When outputs of binary cross entropy are weighted then their errors are scaled",
+				Function:  WithOutputWeights(BinaryCrossEntropyLoss[float64], []float64{0.5, 1, 3}),
+				Actual:    []float64{1, 0, 1},
+				Predicted: probabilities,
+				Value:     -(0.5*math.Log(0.2) + math.Log(0.3) + 3*math.Log(0.1)) / 3,
+				Mean:      true,
+			},
+			{
+				Name:      "When sample is weighted then the loss is scaled",
+				Function:  WithSampleWeight(MeanAbsoluteError[float64], 0.5),
+				Actual:    []float64{1, 2, 3},
+				Predicted: []float64{2, 2, 1},
+				Value:     0.5,
+				Mean:      true,
+			},
+			{
+				Name:      "When classes of categorical cross entropy are weighted then the loss of the class is scaled",
+				Function:  CategoricalCrossEntropyWithClassWeights(map[int]float64{1: 3}),
+				Actual:    []float64{0.5, 0.5, 0},
+				Predicted: probabilities,
+				Value:     -0.5*math.Log(0.2) - 1.5*math.Log(0.7),
+			},
+			{
+				Name:      "When classes of softmax cross entropy are weighted then the loss of the class is scaled",
+				Function:  SoftmaxCrossEntropyWithClassWeights(map[int]float64{0: 2, 2: 0.5}),
+				Actual:    []float64{0.5, 0, 0.5},
+				Predicted: []float64{1, 2, 3},
+				Value:     2*0.5*2.40760596444438 + 0.5*0.5*0.4076059644443803,
+			},
+		}
+	)
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var partials, value = test.Function(test.Actual, test.Predicted)
+			assert.InDelta(t, test.Value, value, 1e-9)
+			assert.Len(t, partials, len(test.Actual))
+
+			var scale = 1.0
+			if test.Mean {
+				scale = float64(len(test.Actual))
+			}
+			for i := range test.Predicted {
+				var (
+					plus  = append([]float64(nil), test.Predicted...)
+					minus = append([]float64(nil), test.Predicted...)
+				)
+				plus[i] += h
+				minus[i] -= h
+				var (
+					_, upper = test.Function(test.Actual, plus)
+					_, lower = test.Function(test.Actual, minus)
+				)
+				assert.InDelta(t, -scale*(upper-lower)/(2*h), partials[i], 1e-5, "partials[%d]", i)
+			}
+		})
+	}
+}
+
+func TestClassWeightsDefaultToOne(t *testing.T) {
+	var (
+		actual              = []float64{0.3, 0.7}
+		predicted           = []float64{0.4, 0.6}
+		expected, value     = CategoricalCrossEntropyLoss(actual, predicted)
+		partials, weighted  = CategoricalCrossEntropyWithClassWeights(map[int]float64{})(actual, predicted)
+		softmax, softValue  = SoftmaxCrossEntropyLoss(actual, predicted)
+		weightedSoftmax, ws = SoftmaxCrossEntropyWithClassWeights(map[int]float64{5: 2})(actual, predicted)
+	)
+	assert.Equal(t, expected, partials)
+	assert.Equal(t, value, weighted)
+	assert.InDeltaSlice(t, softmax, weightedSoftmax, 1e-12)
+	assert.InDelta(t, softValue, ws, 1e-12)
+}
+
+func TestWithOutputWeightsAndUnmatchedSizes(t *testing.T) {
+	assert.PanicsWithError(t, errors.UnmatchedSizeOfVectorsError.Error(), func() {
+		_, _ = WithOutputWeights(MeanSquareError[float64], []float64{1})([]float64{1, 2}, []float64{1, 2})
+	})
+}
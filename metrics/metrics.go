@@ -5,8 +5,10 @@ import (
 	"github.com/publiczny81/ml/calculus/utils"
 	"github.com/publiczny81/ml/calculus/vector"
 	"github.com/publiczny81/ml/calculus/vector/pool"
+	"github.com/publiczny81/ml/errors"
 	"github.com/publiczny81/ml/utils/slices"
 	"math"
+	"sync"
 )
 
 const (
@@ -15,20 +17,23 @@ const (
 	Sum       = "sum"
 )
 
-var register = map[string]Metrics{
-	Euclidean: {
-		Name:     Euclidean,
-		Function: EuclideanDistance[[]float64, float64],
-	},
-	Sum: {
-		Name:     Sum,
-		Function: SumDistance[[]float64, float64],
-	},
-	Manhattan: {
-		Name:     Manhattan,
-		Function: ManhattanFunc[[]float64, float64],
-	},
-}
+var (
+	registerLock sync.RWMutex
+	register     = map[string]Metrics{
+		Euclidean: {
+			Name:     Euclidean,
+			Function: EuclideanDistance[[]float64, float64],
+		},
+		Sum: {
+			Name:     Sum,
+			Function: SumDistance[[]float64, float64],
+		},
+		Manhattan: {
+			Name:     Manhattan,
+			Function: ManhattanFunc[[]float64, float64],
+		},
+	}
+)
 
 type Func[S ~[]T, T types.Float] func(S, S) T
 
@@ -37,7 +42,30 @@ type Metrics struct {
 	Function func([]float64, []float64) float64
 }
 
+// Register adds custom metrics under given name, so it can be used by networks and codecs.
+// The name must not be registered yet
+func Register(name string, m Metrics) error {
+	if name == "" {
+		return errors.WithMessage(errors.InvalidParameterValueError, "metrics.Register: name is empty")
+	}
+	if m.Function == nil {
+		return errors.WithMessagef(errors.InvalidParameterError, "metrics.Register: function of %s is nil", name)
+	}
+	registerLock.Lock()
+	defer registerLock.Unlock()
+
+	if _, found := register[name]; found {
+		return errors.WithMessagef(errors.DuplicateNameError, "metrics.Register: %s", name)
+	}
+	m.Name = name
+	register[name] = m
+	return nil
+}
+
 func Get(metrics string) (m Metrics, found bool) {
+	registerLock.RLock()
+	defer registerLock.RUnlock()
+
 	m, found = register[metrics]
 	return
 }
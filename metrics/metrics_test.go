@@ -1,7 +1,9 @@
 package metrics
 
 import (
+	"github.com/publiczny81/ml/errors"
 	"github.com/stretchr/testify/assert"
+	"math"
 	"testing"
 )
 
@@ -98,3 +100,34 @@ func TestGet(t *testing.T) {
 		})
 	}
 }
+
+// unregister removes the metrics registered by the test, so tests can be run repeatedly
+func unregister(t *testing.T, name string) {
+	t.Cleanup(func() {
+		registerLock.Lock()
+		defer registerLock.Unlock()
+		delete(register, name)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	var chebyshev = Metrics{
+		Function: func(x, y []float64) (value float64) {
+			for i := range x {
+				value = max(value, math.Abs(x[i]-y[i]))
+			}
+			return
+		},
+	}
+	unregister(t, "chebyshev")
+	assert.NoError(t, Register("chebyshev", chebyshev))
+	assert.ErrorIs(t, Register("chebyshev", chebyshev), errors.DuplicateNameError)
+	assert.ErrorIs(t, Register(Euclidean, chebyshev), errors.DuplicateNameError)
+	assert.ErrorIs(t, Register("", chebyshev), errors.InvalidParameterValueError)
+	assert.ErrorIs(t, Register("nil", Metrics{}), errors.InvalidParameterError)
+
+	var m, found = Get("chebyshev")
+	assert.True(t, found)
+	assert.Equal(t, "chebyshev", m.Name)
+	assert.Equal(t, 3.0, m.Function([]float64{1, 5}, []float64{2, 2}))
+}
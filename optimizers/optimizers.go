@@ -0,0 +1,204 @@
+package optimizers
+
+import (
+	"github.com/publiczny81/ml/errors"
+	"math"
+	"slices"
+)
+
+const (
+	SGD      = "sgd"
+	Momentum = "momentum"
+	Nesterov = "nesterov"
+	AdaGrad  = "adagrad"
+	RMSProp  = "rmsprop"
+	Adam     = "adam"
+	AdamW    = "adamw"
+)
+
+// names of the per-weight state kept by optimizers
+const (
+	Velocity     = "velocity"
+	Accumulator  = "accumulator"
+	FirstMoment  = "first_moment"
+	SecondMoment = "second_moment"
+)
+
+// State is a serializable snapshot of the optimizer which allows to resume training exactly
+type State struct {
+	// Name of the optimizer
+	Name string `json:"name"`
+	// Steps is the number of steps done so far
+	Steps int `json:"steps"`
+	// Slots contains per-weight state of the optimizer
+	Slots map[string][]float64 `json:"slots,omitempty"`
+}
+
+type step func(state *State, parameters, gradients []float64, rate float64)
+
+// Optimizer nudges flat parameter vectors with gradients and owns per-weight state required to do that.
+// Gradients point in the direction which decreases the loss, like partials returned by losses.
+type Optimizer struct {
+	state State
+	slots []string
+	step  step
+}
+
+func newOptimizer(name string, step step, slots ...string) *Optimizer {
+	return &Optimizer{
+		state: State{
+			Name: name,
+		},
+		slots: slots,
+		step:  step,
+	}
+}
+
+func (o *Optimizer) Name() string {
+	return o.state.Name
+}
+
+// Step nudges parameters with gradients scaled by the learning rate
+func (o *Optimizer) Step(parameters, gradients []float64, rate float64) {
+	if len(parameters) != len(gradients) {
+		panic(errors.UnmatchedSizeOfVectorsError)
+	}
+	if o.state.Slots == nil && len(o.slots) > 0 {
+		o.state.Slots = make(map[string][]float64, len(o.slots))
+	}
+	for _, slot := range o.slots {
+		if len(o.state.Slots[slot]) != len(parameters) {
+			o.state.Slots[slot] = make([]float64, len(parameters))
+		}
+	}
+	o.state.Steps++
+	o.step(&o.state, parameters, gradients, rate)
+}
+
+// State returns a copy of the current state of the optimizer
+func (o *Optimizer) State() (s State) {
+	s.Name = o.state.Name
+	s.Steps = o.state.Steps
+	if o.state.Slots == nil {
+		return
+	}
+	s.Slots = make(map[string][]float64, len(o.state.Slots))
+	for k, v := range o.state.Slots {
+		s.Slots[k] = slices.Clone(v)
+	}
+	return
+}
+
+// SetState restores the state of the optimizer, e.g. decoded from a model file
+func (o *Optimizer) SetState(s State) error {
+	if s.Name != o.state.Name {
+		return errors.WithMessagef(errors.InvalidParameterValueError, "state.Name=%s", s.Name)
+	}
+	if s.Steps < 0 {
+		return errors.WithMessagef(errors.InvalidParameterValueError, "state.Steps=%d", s.Steps)
+	}
+	var size = -1
+	for _, slot := range o.slots {
+		values, found := s.Slots[slot]
+		if !found {
+			if s.Steps == 0 {
+				continue
+			}
+			return errors.WithMessagef(errors.InvalidParameterValueError, "state.Slots[%s] is missing", slot)
+		}
+		if size >= 0 && len(values) != size {
+			return errors.WithMessagef(errors.UnmatchedSizeOfVectorsError, "len(state.Slots[%s])=%d", slot, len(values))
+		}
+		size = len(values)
+	}
+	o.state = State{
+		Name:  s.Name,
+		Steps: s.Steps,
+	}
+	if s.Slots != nil {
+		o.state.Slots = make(map[string][]float64, len(s.Slots))
+		for k, v := range s.Slots {
+			o.state.Slots[k] = slices.Clone(v)
+		}
+	}
+	return nil
+}
+
+// NewSGD creates plain stochastic gradient descent which scales gradients by the learning rate
+func NewSGD() *Optimizer {
+	return newOptimizer(SGD, func(_ *State, parameters, gradients []float64, rate float64) {
+		for i, g := range gradients {
+			parameters[i] += rate * g
+		}
+	})
+}
+
+// NewMomentum creates stochastic gradient descent with classical momentum
+func NewMomentum(momentum float64) *Optimizer {
+	return newOptimizer(Momentum, func(state *State, parameters, gradients []float64, rate float64) {
+		var velocity = state.Slots[Velocity]
+		for i, g := range gradients {
+			velocity[i] = momentum*velocity[i] + rate*g
+			parameters[i] += velocity[i]
+		}
+	}, Velocity)
+}
+
+// NewNesterov creates stochastic gradient descent with Nesterov accelerated momentum
+func NewNesterov(momentum float64) *Optimizer {
+	return newOptimizer(Nesterov, func(state *State, parameters, gradients []float64, rate float64) {
+		var velocity = state.Slots[Velocity]
+		for i, g := range gradients {
+			velocity[i] = momentum*velocity[i] + rate*g
+			parameters[i] += momentum*velocity[i] + rate*g
+		}
+	}, Velocity)
+}
+
+// NewAdaGrad creates optimizer which adapts learning rate of each weight to the sum of its squared gradients
+func NewAdaGrad(epsilon float64) *Optimizer {
+	return newOptimizer(AdaGrad, func(state *State, parameters, gradients []float64, rate float64) {
+		var accumulator = state.Slots[Accumulator]
+		for i, g := range gradients {
+			accumulator[i] += g * g
+			parameters[i] += rate * g / (math.Sqrt(accumulator[i]) + epsilon)
+		}
+	}, Accumulator)
+}
+
+// NewRMSProp creates optimizer which adapts learning rate of each weight to the moving average of its squared gradients
+func NewRMSProp(decay, epsilon float64) *Optimizer {
+	return newOptimizer(RMSProp, func(state *State, parameters, gradients []float64, rate float64) {
+		var accumulator = state.Slots[Accumulator]
+		for i, g := range gradients {
+			accumulator[i] = decay*accumulator[i] + (1-decay)*g*g
+			parameters[i] += rate * g / (math.Sqrt(accumulator[i]) + epsilon)
+		}
+	}, Accumulator)
+}
+
+// NewAdam creates Adam optimizer with bias-corrected estimates of the first and second moments
+func NewAdam(beta1, beta2, epsilon float64) *Optimizer {
+	return newOptimizer(Adam, adam(beta1, beta2, epsilon, 0), FirstMoment, SecondMoment)
+}
+
+// NewAdamW creates Adam optimizer with decoupled weight decay
+func NewAdamW(beta1, beta2, epsilon, weightDecay float64) *Optimizer {
+	return newOptimizer(AdamW, adam(beta1, beta2, epsilon, weightDecay), FirstMoment, SecondMoment)
+}
+
+func adam(beta1, beta2, epsilon, weightDecay float64) step {
+	return func(state *State, parameters, gradients []float64, rate float64) {
+		var (
+			m           = state.Slots[FirstMoment]
+			v           = state.Slots[SecondMoment]
+			correction1 = 1 - math.Pow(beta1, float64(state.Steps))
+			correction2 = 1 - math.Pow(beta2, float64(state.Steps))
+		)
+		for i, g := range gradients {
+			m[i] = beta1*m[i] + (1-beta1)*g
+			v[i] = beta2*v[i] + (1-beta2)*g*g
+			parameters[i] += rate*(m[i]/correction1)/(math.Sqrt(v[i]/correction2)+epsilon) - rate*weightDecay*parameters[i]
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package optimizers
+
+import (
+	"encoding/json"
+	"github.com/publiczny81/ml/errors"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type OptimizerSuite struct {
+	suite.Suite
+}
+
+func TestOptimizer(t *testing.T) {
+	suite.Run(t, new(OptimizerSuite))
+}
+
+func (s *OptimizerSuite) TestStep() {
+	var tests = []struct {
+		Name      string
+		Optimizer *Optimizer
+		Steps     int
+		Expected  []float64
+	}{
+		{
+			Name:      "When optimizer is SGD then parameters are nudged with scaled gradients",
+			Optimizer: NewSGD(),
+			Steps:     2,
+			Expected:  []float64{1.2, -0.4},
+		},
+		{
+			Name:      "When optimizer is Momentum then velocity accumulates",
+			Optimizer: NewMomentum(0.5),
+			Steps:     2,
+			Expected:  []float64{1.25, -0.5},
+		},
+		{
+			Name:      "When optimizer is Nesterov then parameters look ahead",
+			Optimizer: NewNesterov(0.5),
+			Steps:     1,
+			Expected:  []float64{1.15, -0.3},
+		},
+		{
+			Name:      "When optimizer is AdaGrad then gradients are normalized by accumulated squares",
+			Optimizer: NewAdaGrad(0),
+			Steps:     1,
+			Expected:  []float64{1.1, -0.1},
+		},
+		{
+			Name:      "When optimizer is RMSProp then gradients are normalized by moving average of squares",
+			Optimizer: NewRMSProp(0.75, 0),
+			Steps:     1,
+			Expected:  []float64{1.2, -0.2},
+		},
+		{
+			Name:      "When optimizer is Adam then first step is equal to learning rate",
+			Optimizer: NewAdam(0.9, 0.999, 0),
+			Steps:     1,
+			Expected:  []float64{1.1, -0.1},
+		},
+		{
+			Name:      "When optimizer is AdamW then weights decay",
+			Optimizer: NewAdamW(0.9, 0.999, 0, 0.5),
+			Steps:     1,
+			Expected:  []float64{1.05, -0.1},
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.Name, func() {
+			var parameters = []float64{1, 0}
+			for range test.Steps {
+				test.Optimizer.Step(parameters, []float64{1, -2}, 0.1)
+			}
+			s.InDeltaSlice(test.Expected, parameters, 1e-9)
+			s.Equal(test.Steps, test.Optimizer.State().Steps)
+		})
+	}
+}
+
+func (s *OptimizerSuite) TestStepWithUnmatchedSizes() {
+	s.PanicsWithError(errors.UnmatchedSizeOfVectorsError.Error(), func() {
+		NewSGD().Step([]float64{1, 2}, []float64{1}, 0.1)
+	})
+}
+
+func (s *OptimizerSuite) TestResume() {
+	var (
+		gradients   = [][]float64{{1, -2}, {0.5, 0.3}, {-1, 1}, {0.2, 0.2}}
+		expected    = []float64{1, 0}
+		actual      = []float64{1, 0}
+		interrupted = NewAdam(0.9, 0.999, 1e-8)
+		resumed     = NewAdam(0.9, 0.999, 1e-8)
+		continuous  = NewAdam(0.9, 0.999, 1e-8)
+	)
+	for _, g := range gradients {
+		continuous.Step(expected, g, 0.1)
+	}
+	for _, g := range gradients[:2] {
+		interrupted.Step(actual, g, 0.1)
+	}
+	buffer, err := json.Marshal(interrupted.State())
+	s.NoError(err)
+
+	var state State
+	s.NoError(json.Unmarshal(buffer, &state))
+	s.NoError(resumed.SetState(state))
+
+	for _, g := range gradients[2:] {
+		resumed.Step(actual, g, 0.1)
+	}
+	s.Equal(expected, actual)
+	s.Equal(continuous.State(), resumed.State())
+}
+
+func (s *OptimizerSuite) TestSetState() {
+	var tests = []struct {
+		Name      string
+		Optimizer *Optimizer
+		State     State
+		Error     error
+	}{
+		{
+			Name:      "When state belongs to other optimizer then return error",
+			Optimizer: NewAdam(0.9, 0.999, 1e-8),
+			State:     State{Name: SGD},
+			Error:     errors.InvalidParameterValueError,
+		},
+		{
+			Name:      "When state misses a slot then return error",
+			Optimizer: NewAdam(0.9, 0.999, 1e-8),
+			State:     State{Name: Adam, Steps: 1, Slots: map[string][]float64{FirstMoment: {1}}},
+			Error:     errors.InvalidParameterValueError,
+		},
+		{
+			Name:      "When slots have different sizes then return error",
+			Optimizer: NewAdam(0.9, 0.999, 1e-8),
+			State:     State{Name: Adam, Steps: 1, Slots: map[string][]float64{FirstMoment: {1}, SecondMoment: {1, 2}}},
+			Error:     errors.UnmatchedSizeOfVectorsError,
+		},
+		{
+			Name:      "When state is fresh then return no error",
+			Optimizer: NewMomentum(0.9),
+			State:     State{Name: Momentum},
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.Name, func() {
+			var err = test.Optimizer.SetState(test.State)
+			if test.Error != nil {
+				s.ErrorIs(err, test.Error)
+				return
+			}
+			s.NoError(err)
+			s.Equal(test.State, test.Optimizer.State())
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package preprocessing
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/sampling"
+	"math"
+	"slices"
+)
+
+// categories learns sorted distinct values of given columns
+func categories(ctx context.Context, source sampling.Source[[]float64], columns []int) (width int, categories [][]float64, err error) {
+	width = -1
+	categories = make([][]float64, len(columns))
+	err = scan(ctx, source, func(x []float64) error {
+		if width < 0 {
+			width = len(x)
+		}
+		if err := check(x, width); err != nil {
+			return err
+		}
+		for j, column := range columns {
+			if column < 0 || column >= width {
+				return errors.WithMessagef(errors.InvalidParameterValueError, "column=%d", column)
+			}
+			if v := x[column]; !math.IsNaN(v) && !slices.Contains(categories[j], v) {
+				categories[j] = append(categories[j], v)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	for j, c := range categories {
+		if len(c) == 0 {
+			return 0, nil, errors.WithMessagef(errors.InvalidParameterValueError, "column=%d, categories=0", columns[j])
+		}
+		slices.Sort(c)
+	}
+	return max(0, width), categories, nil
+}
+
+// category returns index of the value among categories of the column
+func category(categories []float64, column int, value float64) (int, error) {
+	var i, found = slices.BinarySearch(categories, value)
+	if !found {
+		return 0, errors.WithMessagef(errors.InvalidParameterValueError, "column=%d, category=%v", column, value)
+	}
+	return i, nil
+}
+
+// OneHotEncoder replaces each categorical column with as many columns as it has categories. The column
+// of the category of the value is 1 and the others are 0. Other columns are kept in their order
+type OneHotEncoder struct {
+	Columns    []int       `json:"columns"`
+	Width      int         `json:"width"`
+	Categories [][]float64 `json:"categories"`
+}
+
+// NewOneHotEncoder creates encoder of given columns
+func NewOneHotEncoder(columns ...int) *OneHotEncoder {
+	return &OneHotEncoder{
+		Columns: columns,
+	}
+}
+
+func (e *OneHotEncoder) Name() string {
+	return OneHot
+}
+
+func (e *OneHotEncoder) Fit(ctx context.Context, source sampling.Source[[]float64]) (err error) {
+	e.Width, e.Categories, err = categories(ctx, source, e.Columns)
+	return
+}
+
+func (e *OneHotEncoder) Transform(x []float64) (y []float64, err error) {
+	if err = check(x, e.Width); err != nil {
+		return
+	}
+	y = make([]float64, 0, len(x))
+	for i, v := range x {
+		var j = slices.Index(e.Columns, i)
+		if j < 0 {
+			y = append(y, v)
+			continue
+		}
+		var c int
+		if c, err = category(e.Categories[j], i, v); err != nil {
+			return nil, err
+		}
+		var encoded = make([]float64, len(e.Categories[j]))
+		encoded[c] = 1
+		y = append(y, encoded...)
+	}
+	return
+}
+
+func (e *OneHotEncoder) InverseTransform(y []float64) (x []float64, err error) {
+	var width = e.Width
+	for _, c := range e.Categories {
+		width += len(c) - 1
+	}
+	if err = check(y, width); err != nil {
+		return
+	}
+	x = make([]float64, e.Width)
+	for i := range x {
+		var j = slices.Index(e.Columns, i)
+		if j < 0 {
+			x[i], y = y[0], y[1:]
+			continue
+		}
+		if len(e.Categories[j]) == 0 {
+			return nil, errors.WithMessagef(errors.InvalidParameterValueError, "column=%d, categories=0", i)
+		}
+		var encoded = y[:len(e.Categories[j])]
+		x[i], y = e.Categories[j][argmax(encoded)], y[len(encoded):]
+	}
+	return
+}
+
+// OrdinalEncoder replaces values of categorical columns with indices of their categories in ascending order
+type OrdinalEncoder struct {
+	Columns    []int       `json:"columns"`
+	Width      int         `json:"width"`
+	Categories [][]float64 `json:"categories"`
+}
+
+// NewOrdinalEncoder creates encoder of given columns
+func NewOrdinalEncoder(columns ...int) *OrdinalEncoder {
+	return &OrdinalEncoder{
+		Columns: columns,
+	}
+}
+
+func (e *OrdinalEncoder) Name() string {
+	return Ordinal
+}
+
+func (e *OrdinalEncoder) Fit(ctx context.Context, source sampling.Source[[]float64]) (err error) {
+	e.Width, e.Categories, err = categories(ctx, source, e.Columns)
+	return
+}
+
+func (e *OrdinalEncoder) Transform(x []float64) (y []float64, err error) {
+	if err = check(x, e.Width); err != nil {
+		return
+	}
+	y = slices.Clone(x)
+	for j, column := range e.Columns {
+		var c int
+		if c, err = category(e.Categories[j], column, x[column]); err != nil {
+			return nil, err
+		}
+		y[column] = float64(c)
+	}
+	return
+}
+
+func (e *OrdinalEncoder) InverseTransform(y []float64) (x []float64, err error) {
+	if err = check(y, e.Width); err != nil {
+		return
+	}
+	x = slices.Clone(y)
+	for j, column := range e.Columns {
+		var c = int(math.Round(y[column]))
+		if c < 0 || c >= len(e.Categories[j]) {
+			return nil, errors.WithMessagef(errors.InvalidParameterValueError, "column=%d, index=%v", column, y[column])
+		}
+		x[column] = e.Categories[j][c]
+	}
+	return
+}
+
+// argmax returns index of the largest value
+func argmax(values []float64) (idx int) {
+	for i, v := range values {
+		if v > values[idx] {
+			idx = i
+		}
+	}
+	return
+}
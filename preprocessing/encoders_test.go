@@ -0,0 +1,64 @@
+package preprocessing
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/sampling"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+var categorical = sampling.NewSliceSource([][]float64{{3, 0.5, 1}, {1, 1.5, 0}, {2, 2.5, 1}})
+
+func TestOneHotEncoder(t *testing.T) {
+	var encoder = NewOneHotEncoder(0, 2)
+	assert.NoError(t, encoder.Fit(context.TODO(), categorical))
+	assert.Equal(t, [][]float64{{1, 2, 3}, {0, 1}}, encoder.Categories)
+
+	var y, err = encoder.Transform([]float64{2, 7, 0})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 1, 0, 7, 1, 0}, y)
+
+	var x []float64
+	x, err = encoder.InverseTransform(y)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 7, 0}, x)
+
+	_, err = encoder.Transform([]float64{4, 7, 0})
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+	_, err = encoder.InverseTransform([]float64{1})
+	assert.ErrorIs(t, err, errors.UnmatchedSizeOfVectorsError)
+	assert.ErrorIs(t, NewOneHotEncoder(3).Fit(context.TODO(), categorical), errors.InvalidParameterValueError)
+}
+
+func TestOrdinalEncoder(t *testing.T) {
+	var encoder = NewOrdinalEncoder(0)
+	assert.NoError(t, encoder.Fit(context.TODO(), categorical))
+
+	var y, err = encoder.Transform([]float64{3, 7, 0})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 7, 0}, y)
+
+	var x []float64
+	x, err = encoder.InverseTransform(y)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{3, 7, 0}, x)
+
+	_, err = encoder.InverseTransform([]float64{5, 7, 0})
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+}
+
+func TestEncodersRejectColumnWithoutCategories(t *testing.T) {
+	var source = sampling.NewSliceSource([][]float64{{1, math.NaN()}, {2, math.NaN()}})
+	assert.ErrorIs(t, NewOneHotEncoder(1).Fit(context.TODO(), source), errors.InvalidParameterValueError)
+	assert.ErrorIs(t, NewOrdinalEncoder(1).Fit(context.TODO(), source), errors.InvalidParameterValueError)
+
+	var encoder = &OneHotEncoder{Columns: []int{1}, Width: 2, Categories: [][]float64{{}}}
+	var _, err = encoder.InverseTransform([]float64{1})
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+	_, err = encoder.Transform([]float64{1, 0})
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+	_, err = (&OrdinalEncoder{Columns: []int{1}, Width: 2, Categories: [][]float64{{}}}).InverseTransform([]float64{1, 0})
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+}
@@ -0,0 +1,90 @@
+package preprocessing
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/sampling"
+	"math"
+	"slices"
+)
+
+// strategies of imputation
+const (
+	Mean     = "mean"
+	Median   = "median"
+	Constant = "constant"
+)
+
+// Imputer replaces missing (NaN) values of features with their mean, median or the constant. Missing values
+// of features which have no values at all are replaced with zero
+type Imputer struct {
+	Strategy string    `json:"strategy"`
+	Value    float64   `json:"value,omitempty"`
+	Fill     []float64 `json:"fill"`
+}
+
+// NewImputer creates imputer with the strategy Mean or Median
+func NewImputer(strategy string) *Imputer {
+	return &Imputer{
+		Strategy: strategy,
+	}
+}
+
+// NewConstantImputer creates imputer which replaces missing values with the value
+func NewConstantImputer(value float64) *Imputer {
+	return &Imputer{
+		Strategy: Constant,
+		Value:    value,
+	}
+}
+
+func (i *Imputer) Name() string {
+	return Imputation
+}
+
+func (i *Imputer) Fit(ctx context.Context, source sampling.Source[[]float64]) error {
+	if i.Strategy != Mean && i.Strategy != Median && i.Strategy != Constant {
+		return errors.WithMessagef(errors.InvalidParameterValueError, "strategy=%s", i.Strategy)
+	}
+	var values, err = columns(ctx, source)
+	if err != nil {
+		return err
+	}
+	i.Fill = make([]float64, len(values))
+	for j, column := range values {
+		switch {
+		case i.Strategy == Constant:
+			i.Fill[j] = i.Value
+		case len(column) == 0:
+		case i.Strategy == Mean:
+			for _, v := range column {
+				i.Fill[j] += v / float64(len(column))
+			}
+		case i.Strategy == Median:
+			slices.Sort(column)
+			i.Fill[j] = quantile(column, 0.5)
+		}
+	}
+	return nil
+}
+
+func (i *Imputer) Transform(x []float64) (y []float64, err error) {
+	if err = check(x, len(i.Fill)); err != nil {
+		return
+	}
+	y = slices.Clone(x)
+	for j, v := range y {
+		if math.IsNaN(v) {
+			y[j] = i.Fill[j]
+		}
+	}
+	return
+}
+
+// InverseTransform returns copy of the vector, because positions of missing values are not kept
+func (i *Imputer) InverseTransform(y []float64) (x []float64, err error) {
+	if err = check(y, len(i.Fill)); err != nil {
+		return
+	}
+	return slices.Clone(y), nil
+}
@@ -0,0 +1,39 @@
+package preprocessing
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/sampling"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestImputer(t *testing.T) {
+	var (
+		nan    = math.NaN()
+		source = sampling.NewSliceSource([][]float64{{1, nan, nan}, {2, 4, nan}, {6, 5, nan}})
+		tests  = []struct {
+			Name     string
+			Imputer  *Imputer
+			Expected []float64
+		}{
+			{Name: "Mean", Imputer: NewImputer(Mean), Expected: []float64{3, 4.5, 0}},
+			{Name: "Median", Imputer: NewImputer(Median), Expected: []float64{2, 4.5, 0}},
+			{Name: "Constant", Imputer: NewConstantImputer(-1), Expected: []float64{-1, -1, -1}},
+		}
+	)
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.NoError(t, test.Imputer.Fit(context.TODO(), source))
+			var y, err = test.Imputer.Transform([]float64{nan, nan, nan})
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, y)
+
+			y, err = test.Imputer.Transform([]float64{7, 8, 9})
+			assert.NoError(t, err)
+			assert.Equal(t, []float64{7, 8, 9}, y)
+		})
+	}
+	assert.ErrorIs(t, NewImputer("mode").Fit(context.TODO(), source), errors.InvalidParameterValueError)
+}
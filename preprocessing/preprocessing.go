@@ -0,0 +1,163 @@
+package preprocessing
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/sampling"
+	"math"
+)
+
+const (
+	Standard      = "standard"
+	MinMax        = "min_max"
+	Robust        = "robust"
+	Normalization = "normalization"
+	OneHot        = "one_hot"
+	Ordinal       = "ordinal"
+	Imputation    = "imputation"
+)
+
+// Preprocessor learns parameters of the transformation from samples and transforms feature vectors.
+// Fitted parameters are exported, so preprocessors can be serialized to JSON
+type Preprocessor interface {
+	Name() string
+	// Fit learns parameters of the transformation from vectors of the source
+	Fit(ctx context.Context, source sampling.Source[[]float64]) error
+	// Transform returns transformed copy of the vector
+	Transform(x []float64) ([]float64, error)
+	// InverseTransform reverts Transform
+	InverseTransform(y []float64) ([]float64, error)
+}
+
+var preprocessors = map[string]func() Preprocessor{
+	Standard:      func() Preprocessor { return NewStandardScaler() },
+	MinMax:        func() Preprocessor { return NewMinMaxScaler(0, 1) },
+	Robust:        func() Preprocessor { return NewRobustScaler() },
+	Normalization: func() Preprocessor { return new(Normalizer) },
+	OneHot:        func() Preprocessor { return NewOneHotEncoder() },
+	Ordinal:       func() Preprocessor { return NewOrdinalEncoder() },
+	Imputation:    func() Preprocessor { return new(Imputer) },
+}
+
+// Pipeline applies preprocessors one after another. Each preprocessor is fitted on vectors transformed
+// by the preceding ones
+type Pipeline []Preprocessor
+
+func (p Pipeline) Fit(ctx context.Context, source sampling.Source[[]float64]) (err error) {
+	for i, preprocessor := range p {
+		if err = preprocessor.Fit(ctx, source); err != nil {
+			return errors.WithMessagef(err, "pipeline[%d]=%s", i, preprocessor.Name())
+		}
+		source = sampling.NewMapSource[[]float64, []float64](source, preprocessor.Transform)
+	}
+	return
+}
+
+func (p Pipeline) Transform(x []float64) (y []float64, err error) {
+	y = x
+	for i, preprocessor := range p {
+		if y, err = preprocessor.Transform(y); err != nil {
+			return nil, errors.WithMessagef(err, "pipeline[%d]=%s", i, preprocessor.Name())
+		}
+	}
+	return
+}
+
+func (p Pipeline) InverseTransform(y []float64) (x []float64, err error) {
+	x = y
+	for i := len(p) - 1; i >= 0; i-- {
+		if x, err = p[i].InverseTransform(x); err != nil {
+			return nil, errors.WithMessagef(err, "pipeline[%d]=%s", i, p[i].Name())
+		}
+	}
+	return
+}
+
+// step is serialized form of the preprocessor of the pipeline
+type step struct {
+	Name  string          `json:"name"`
+	State json.RawMessage `json:"state"`
+}
+
+func (p Pipeline) MarshalJSON() ([]byte, error) {
+	var steps = make([]step, len(p))
+	for i, preprocessor := range p {
+		var state, err = json.Marshal(preprocessor)
+		if err != nil {
+			return nil, err
+		}
+		steps[i] = step{Name: preprocessor.Name(), State: state}
+	}
+	return json.Marshal(steps)
+}
+
+func (p *Pipeline) UnmarshalJSON(data []byte) (err error) {
+	var steps []step
+	if err = json.Unmarshal(data, &steps); err != nil {
+		return
+	}
+	var pipeline = make(Pipeline, len(steps))
+	for i, s := range steps {
+		var create, found = preprocessors[s.Name]
+		if !found {
+			return errors.WithMessagef(errors.UnknownNameError, "pipeline[%d]=%s", i, s.Name)
+		}
+		pipeline[i] = create()
+		if err = json.Unmarshal(s.State, pipeline[i]); err != nil {
+			return errors.WithMessagef(err, "pipeline[%d]=%s", i, s.Name)
+		}
+	}
+	*p = pipeline
+	return
+}
+
+// scan calls f with each vector of the source
+func scan(ctx context.Context, source sampling.Source[[]float64], f func(x []float64) error) (err error) {
+	var count int
+	if count, err = source.Count(ctx); err != nil {
+		return
+	}
+	for i := range count {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		var x []float64
+		if x, err = source.Select(ctx, i); err != nil {
+			return
+		}
+		if err = f(x); err != nil {
+			return errors.WithMessagef(err, "sample=%d", i)
+		}
+	}
+	return
+}
+
+// columns collects values of each feature of the source skipping missing (NaN) values
+func columns(ctx context.Context, source sampling.Source[[]float64]) (values [][]float64, err error) {
+	var width = -1
+	err = scan(ctx, source, func(x []float64) error {
+		if width < 0 {
+			width = len(x)
+			values = make([][]float64, width)
+		}
+		if len(x) != width {
+			return errors.WithMessagef(errors.UnmatchedSizeOfVectorsError, "len(x)=%d, expected=%d", len(x), width)
+		}
+		for j, v := range x {
+			if !math.IsNaN(v) {
+				values[j] = append(values[j], v)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// check returns error when the size of the vector is not the size of fitted vectors
+func check(x []float64, width int) error {
+	if len(x) != width {
+		return errors.WithMessagef(errors.UnmatchedSizeOfVectorsError, "len(x)=%d, expected=%d", len(x), width)
+	}
+	return nil
+}
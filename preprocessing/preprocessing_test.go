@@ -0,0 +1,55 @@
+package preprocessing
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/metrics"
+	"github.com/publiczny81/ml/sampling"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	var (
+		source   = sampling.NewSliceSource([][]float64{{0, math.NaN()}, {1, 2}, {0, 4}})
+		pipeline = Pipeline{NewImputer(Mean), NewOneHotEncoder(0), NewStandardScaler()}
+	)
+	assert.NoError(t, pipeline.Fit(context.TODO(), source))
+
+	var y, err = pipeline.Transform([]float64{1, math.NaN()})
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{-math.Sqrt2, math.Sqrt2, 0}, y, 1e-9)
+
+	var x []float64
+	x, err = pipeline.InverseTransform(y)
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{1, 3}, x, 1e-9)
+
+	_, err = pipeline.Transform([]float64{2, 1})
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+	assert.ErrorContains(t, err, "pipeline[1]=one_hot")
+}
+
+func TestPipelineJSON(t *testing.T) {
+	var pipeline = Pipeline{
+		NewImputer(Median),
+		NewOrdinalEncoder(1),
+		NewOneHotEncoder(0),
+		NewStandardScaler(),
+		NewMinMaxScaler(-1, 1),
+		NewRobustScaler(),
+		NewNormalizer(metrics.Euclidean),
+	}
+	assert.NoError(t, pipeline.Fit(context.TODO(), sampling.NewSliceSource([][]float64{{0, 5, 1}, {1, 7, 2}, {0, 5, 4}})))
+
+	var data, err = json.Marshal(pipeline)
+	assert.NoError(t, err)
+
+	var actual Pipeline
+	assert.NoError(t, json.Unmarshal(data, &actual))
+	assert.Equal(t, pipeline, actual)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`[{"name":"unknown","state":{}}]`), &actual), errors.UnknownNameError)
+}
@@ -0,0 +1,259 @@
+package preprocessing
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/metrics"
+	"github.com/publiczny81/ml/sampling"
+	"math"
+	"slices"
+)
+
+// StandardScaler shifts features to zero mean and scales them to unit variance. Features with zero variance
+// are only shifted
+type StandardScaler struct {
+	Mean  []float64 `json:"mean"`
+	Scale []float64 `json:"scale"`
+}
+
+func NewStandardScaler() *StandardScaler {
+	return new(StandardScaler)
+}
+
+func (s *StandardScaler) Name() string {
+	return Standard
+}
+
+func (s *StandardScaler) Fit(ctx context.Context, source sampling.Source[[]float64]) error {
+	var values, err = columns(ctx, source)
+	if err != nil {
+		return err
+	}
+	s.Mean = make([]float64, len(values))
+	s.Scale = make([]float64, len(values))
+	for j, column := range values {
+		for _, v := range column {
+			s.Mean[j] += v / float64(len(column))
+		}
+		var variance float64
+		for _, v := range column {
+			variance += (v - s.Mean[j]) * (v - s.Mean[j]) / float64(len(column))
+		}
+		s.Scale[j] = nonZero(math.Sqrt(variance))
+	}
+	return nil
+}
+
+func (s *StandardScaler) Transform(x []float64) (y []float64, err error) {
+	if err = check(x, len(s.Mean)); err != nil {
+		return
+	}
+	y = make([]float64, len(x))
+	for i, v := range x {
+		y[i] = (v - s.Mean[i]) / s.Scale[i]
+	}
+	return
+}
+
+func (s *StandardScaler) InverseTransform(y []float64) (x []float64, err error) {
+	if err = check(y, len(s.Mean)); err != nil {
+		return
+	}
+	x = make([]float64, len(y))
+	for i, v := range y {
+		x[i] = v*s.Scale[i] + s.Mean[i]
+	}
+	return
+}
+
+// MinMaxScaler scales features linearly into the range. Features with single value are mapped to the lower bound
+type MinMaxScaler struct {
+	Range [2]float64 `json:"range"`
+	Min   []float64  `json:"min"`
+	Max   []float64  `json:"max"`
+}
+
+// NewMinMaxScaler creates scaler into range <lower, upper>
+func NewMinMaxScaler(lower, upper float64) *MinMaxScaler {
+	return &MinMaxScaler{
+		Range: [2]float64{lower, upper},
+	}
+}
+
+func (s *MinMaxScaler) Name() string {
+	return MinMax
+}
+
+func (s *MinMaxScaler) Fit(ctx context.Context, source sampling.Source[[]float64]) error {
+	var values, err = columns(ctx, source)
+	if err != nil {
+		return err
+	}
+	s.Min = make([]float64, len(values))
+	s.Max = make([]float64, len(values))
+	for j, column := range values {
+		if len(column) > 0 {
+			s.Min[j], s.Max[j] = slices.Min(column), slices.Max(column)
+		}
+	}
+	return nil
+}
+
+// scale returns the ratio of the range to the range of the feature
+func (s *MinMaxScaler) scale(i int) float64 {
+	if s.Max[i] == s.Min[i] {
+		return 0
+	}
+	return (s.Range[1] - s.Range[0]) / (s.Max[i] - s.Min[i])
+}
+
+func (s *MinMaxScaler) Transform(x []float64) (y []float64, err error) {
+	if err = check(x, len(s.Min)); err != nil {
+		return
+	}
+	y = make([]float64, len(x))
+	for i, v := range x {
+		y[i] = (v-s.Min[i])*s.scale(i) + s.Range[0]
+	}
+	return
+}
+
+func (s *MinMaxScaler) InverseTransform(y []float64) (x []float64, err error) {
+	if err = check(y, len(s.Min)); err != nil {
+		return
+	}
+	x = make([]float64, len(y))
+	for i, v := range y {
+		if scale := s.scale(i); scale != 0 {
+			x[i] = (v-s.Range[0])/scale + s.Min[i]
+		} else {
+			x[i] = s.Min[i]
+		}
+	}
+	return
+}
+
+// RobustScaler shifts features by their median and scales them by their interquartile range, so it is not
+// sensitive to outliers. Features with zero interquartile range are only shifted
+type RobustScaler struct {
+	Median []float64 `json:"median"`
+	Scale  []float64 `json:"scale"`
+}
+
+func NewRobustScaler() *RobustScaler {
+	return new(RobustScaler)
+}
+
+func (s *RobustScaler) Name() string {
+	return Robust
+}
+
+func (s *RobustScaler) Fit(ctx context.Context, source sampling.Source[[]float64]) error {
+	var values, err = columns(ctx, source)
+	if err != nil {
+		return err
+	}
+	s.Median = make([]float64, len(values))
+	s.Scale = make([]float64, len(values))
+	for j, column := range values {
+		slices.Sort(column)
+		s.Median[j] = quantile(column, 0.5)
+		s.Scale[j] = nonZero(quantile(column, 0.75) - quantile(column, 0.25))
+	}
+	return nil
+}
+
+func (s *RobustScaler) Transform(x []float64) (y []float64, err error) {
+	if err = check(x, len(s.Median)); err != nil {
+		return
+	}
+	y = make([]float64, len(x))
+	for i, v := range x {
+		y[i] = (v - s.Median[i]) / s.Scale[i]
+	}
+	return
+}
+
+func (s *RobustScaler) InverseTransform(y []float64) (x []float64, err error) {
+	if err = check(y, len(s.Median)); err != nil {
+		return
+	}
+	x = make([]float64, len(y))
+	for i, v := range y {
+		x[i] = v*s.Scale[i] + s.Median[i]
+	}
+	return
+}
+
+// Normalizer scales each vector to unit norm given by the metrics, e.g. metrics.Euclidean. Vectors with zero norm
+// are not changed. Norms of vectors are not kept, so the transformation cannot be inverted
+type Normalizer struct {
+	Metrics string `json:"metrics"`
+}
+
+func NewNormalizer(metrics string) *Normalizer {
+	return &Normalizer{
+		Metrics: metrics,
+	}
+}
+
+func (n *Normalizer) Name() string {
+	return Normalization
+}
+
+// Fit checks whether the metrics is registered. Normalizer has nothing to learn
+func (n *Normalizer) Fit(context.Context, sampling.Source[[]float64]) error {
+	var _, err = n.metrics()
+	return err
+}
+
+func (n *Normalizer) metrics() (m metrics.Metrics, err error) {
+	var found bool
+	if m, found = metrics.Get(n.Metrics); !found {
+		err = errors.WithMessagef(errors.UnknownNameError, "metrics=%s", n.Metrics)
+	}
+	return
+}
+
+func (n *Normalizer) Transform(x []float64) (y []float64, err error) {
+	var m metrics.Metrics
+	if m, err = n.metrics(); err != nil {
+		return
+	}
+	y = slices.Clone(x)
+	var norm = m.Function(x, make([]float64, len(x)))
+	if norm == 0 {
+		return
+	}
+	for i := range y {
+		y[i] /= norm
+	}
+	return
+}
+
+func (n *Normalizer) InverseTransform([]float64) ([]float64, error) {
+	return nil, errors.WithMessage(errors.InvalidParameterError, "normalization cannot be inverted")
+}
+
+// quantile returns the quantile of sorted values using linear interpolation
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	var (
+		position = q * float64(len(sorted)-1)
+		lower    = int(position)
+	)
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	return sorted[lower] + (position-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// nonZero returns 1 instead of zero scale
+func nonZero(scale float64) float64 {
+	if scale == 0 {
+		return 1
+	}
+	return scale
+}
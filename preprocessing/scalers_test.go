@@ -0,0 +1,97 @@
+package preprocessing
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/metrics"
+	"github.com/publiczny81/ml/sampling"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+var data = sampling.NewSliceSource([][]float64{{1, 10, 5}, {2, 20, 5}, {3, 30, 5}, {4, 100, 5}})
+
+func TestScalers(t *testing.T) {
+	var tests = []struct {
+		Name         string
+		Preprocessor Preprocessor
+		Input        []float64
+		Expected     []float64
+	}{
+		{
+			Name:         "StandardScaler",
+			Preprocessor: NewStandardScaler(),
+			Input:        []float64{2.5, 40, 6},
+			Expected:     []float64{0, 0, 1},
+		},
+		{
+			Name:         "MinMaxScaler",
+			Preprocessor: NewMinMaxScaler(-1, 1),
+			Input:        []float64{2.5, 55, 5},
+			Expected:     []float64{0, 0, -1},
+		},
+		{
+			Name:         "RobustScaler",
+			Preprocessor: NewRobustScaler(),
+			Input:        []float64{4, 25, 6},
+			Expected:     []float64{1, 0, 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.NoError(t, test.Preprocessor.Fit(context.TODO(), data))
+			var actual, err = test.Preprocessor.Transform(test.Input)
+			assert.NoError(t, err)
+			assert.InDeltaSlice(t, test.Expected, actual, 1e-9)
+
+			_, err = test.Preprocessor.Transform([]float64{1})
+			assert.ErrorIs(t, err, errors.UnmatchedSizeOfVectorsError)
+		})
+	}
+}
+
+func TestScalersAreInvertible(t *testing.T) {
+	for _, preprocessor := range []Preprocessor{NewStandardScaler(), NewMinMaxScaler(0, 1), NewRobustScaler()} {
+		assert.NoError(t, preprocessor.Fit(context.TODO(), data))
+		var (
+			x          = []float64{3.5, -7, 2}
+			y, err     = preprocessor.Transform(x)
+			inverse, _ = preprocessor.InverseTransform(y)
+		)
+		assert.NoError(t, err)
+		if preprocessor.Name() == MinMax {
+			// the feature with single value is mapped to the lower bound
+			x[2] = 5
+		}
+		assert.InDeltaSlice(t, x, inverse, 1e-9, preprocessor.Name())
+	}
+}
+
+func TestStandardScalerSkipsMissingValues(t *testing.T) {
+	var scaler = NewStandardScaler()
+	assert.NoError(t, scaler.Fit(context.TODO(), sampling.NewSliceSource([][]float64{{1}, {math.NaN()}, {3}})))
+	assert.Equal(t, []float64{2}, scaler.Mean)
+	assert.Equal(t, []float64{1}, scaler.Scale)
+}
+
+func TestNormalizer(t *testing.T) {
+	var normalizer = NewNormalizer(metrics.Euclidean)
+	assert.NoError(t, normalizer.Fit(context.TODO(), data))
+
+	var y, err = normalizer.Transform([]float64{3, 4})
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0.6, 0.8}, y, 1e-9)
+
+	y, err = NewNormalizer(metrics.Manhattan).Transform([]float64{1, -3})
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0.25, -0.75}, y, 1e-9)
+
+	y, err = normalizer.Transform([]float64{0, 0})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 0}, y)
+
+	_, err = normalizer.InverseTransform(y)
+	assert.ErrorIs(t, err, errors.InvalidParameterError)
+	assert.ErrorIs(t, NewNormalizer("unknown").Fit(context.TODO(), data), errors.UnknownNameError)
+}
@@ -0,0 +1,388 @@
+package sampling
+
+import (
+	"container/list"
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"sync"
+)
+
+// MapSource transforms elements of the source lazily when they are selected
+type MapSource[E, F any] struct {
+	source Source[E]
+	f      func(E) (F, error)
+}
+
+func NewMapSource[E, F any](source Source[E], f func(E) (F, error)) *MapSource[E, F] {
+	return &MapSource[E, F]{
+		source: source,
+		f:      f,
+	}
+}
+
+func (s *MapSource[E, F]) Count(ctx context.Context) (int, error) {
+	return s.source.Count(ctx)
+}
+
+func (s *MapSource[E, F]) Select(ctx context.Context, idx int) (f F, err error) {
+	var e E
+	if e, err = s.source.Select(ctx, idx); err != nil {
+		return
+	}
+	if f, err = s.f(e); err != nil {
+		err = errors.WithMessagef(err, "MapSource: idx=%d", idx)
+	}
+	return
+}
+
+// selectWeighted transforms the element and keeps its weight
+func (s *MapSource[E, F]) selectWeighted(ctx context.Context, idx int) (f Sample[F], err error) {
+	var e Sample[E]
+	if e, err = selectSample(ctx, s.source, idx); err != nil {
+		return
+	}
+	f.Weight, f.Weighted = e.Weight, e.Weighted
+	if f.Value, err = s.f(e.Value); err != nil {
+		err = errors.WithMessagef(err, "MapSource: idx=%d", idx)
+	}
+	return
+}
+
+func (s *MapSource[E, F]) Weight(ctx context.Context, idx int) (float64, bool, error) {
+	return weight(ctx, s.source, idx)
+}
+
+// FilterSource selects elements of the source which satisfy the predicate. Indices of such elements are computed
+// on the first use, so the source is scanned once
+type FilterSource[E any] struct {
+	source    Source[E]
+	predicate func(E) bool
+
+	mu      sync.Mutex
+	indices []int
+}
+
+func NewFilterSource[E any](source Source[E], predicate func(E) bool) *FilterSource[E] {
+	return &FilterSource[E]{
+		source:    source,
+		predicate: predicate,
+	}
+}
+
+// index scans the source once and records indices of elements which satisfy the predicate
+func (s *FilterSource[E]) index(ctx context.Context) (indices []int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.indices != nil {
+		return s.indices, nil
+	}
+	var count int
+	if count, err = s.source.Count(ctx); err != nil {
+		return
+	}
+	indices = make([]int, 0, count)
+	for i := range count {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		var e E
+		if e, err = s.source.Select(ctx, i); err != nil {
+			return nil, err
+		}
+		if s.predicate(e) {
+			indices = append(indices, i)
+		}
+	}
+	s.indices = indices
+	return
+}
+
+func (s *FilterSource[E]) Count(ctx context.Context) (int, error) {
+	var indices, err = s.index(ctx)
+	return len(indices), err
+}
+
+func (s *FilterSource[E]) Select(ctx context.Context, idx int) (e E, err error) {
+	var indices []int
+	if indices, err = s.index(ctx); err != nil || idx < 0 || idx >= len(indices) {
+		return
+	}
+	return s.source.Select(ctx, indices[idx])
+}
+
+func (s *FilterSource[E]) selectWeighted(ctx context.Context, idx int) (sample Sample[E], err error) {
+	var indices []int
+	if indices, err = s.index(ctx); err != nil || idx < 0 || idx >= len(indices) {
+		return
+	}
+	return selectSample(ctx, s.source, indices[idx])
+}
+
+func (s *FilterSource[E]) Weight(ctx context.Context, idx int) (float64, bool, error) {
+	var indices, err = s.index(ctx)
+	if err != nil || idx < 0 || idx >= len(indices) {
+		return 0, false, err
+	}
+	return weight(ctx, s.source, indices[idx])
+}
+
+// ConcatSource joins sources one after another
+type ConcatSource[E any] struct {
+	sources []Source[E]
+}
+
+func NewConcatSource[E any](sources ...Source[E]) *ConcatSource[E] {
+	return &ConcatSource[E]{
+		sources: sources,
+	}
+}
+
+func (s *ConcatSource[E]) Count(ctx context.Context) (count int, err error) {
+	for _, source := range s.sources {
+		var c int
+		if c, err = source.Count(ctx); err != nil {
+			return 0, err
+		}
+		count += c
+	}
+	return
+}
+
+// locate finds the source holding the element at given index and the index of the element within that source
+func (s *ConcatSource[E]) locate(ctx context.Context, idx int) (source Source[E], local int, err error) {
+	if idx < 0 {
+		return
+	}
+	for _, source = range s.sources {
+		var count int
+		if count, err = source.Count(ctx); err != nil {
+			return nil, 0, err
+		}
+		if idx < count {
+			return source, idx, nil
+		}
+		idx -= count
+	}
+	return nil, 0, nil
+}
+
+func (s *ConcatSource[E]) Select(ctx context.Context, idx int) (e E, err error) {
+	var (
+		source Source[E]
+		local  int
+	)
+	if source, local, err = s.locate(ctx, idx); err != nil || source == nil {
+		return
+	}
+	return source.Select(ctx, local)
+}
+
+func (s *ConcatSource[E]) selectWeighted(ctx context.Context, idx int) (sample Sample[E], err error) {
+	var (
+		source Source[E]
+		local  int
+	)
+	if source, local, err = s.locate(ctx, idx); err != nil || source == nil {
+		return
+	}
+	return selectSample(ctx, source, local)
+}
+
+func (s *ConcatSource[E]) Weight(ctx context.Context, idx int) (float64, bool, error) {
+	var source, local, err = s.locate(ctx, idx)
+	if err != nil || source == nil {
+		return 0, false, err
+	}
+	return weight(ctx, source, local)
+}
+
+// TakeSource selects at most n first elements of the source. Unlike LimitedSource it follows the size
+// of the source
+type TakeSource[E any] struct {
+	source Source[E]
+	n      int
+}
+
+func NewTakeSource[E any](source Source[E], n int) *TakeSource[E] {
+	return &TakeSource[E]{
+		source: source,
+		n:      max(0, n),
+	}
+}
+
+func (s *TakeSource[E]) Count(ctx context.Context) (int, error) {
+	var count, err = s.source.Count(ctx)
+	return min(count, s.n), err
+}
+
+func (s *TakeSource[E]) Select(ctx context.Context, idx int) (e E, err error) {
+	if idx < 0 || idx >= s.n {
+		return
+	}
+	return s.source.Select(ctx, idx)
+}
+
+func (s *TakeSource[E]) selectWeighted(ctx context.Context, idx int) (sample Sample[E], err error) {
+	if idx < 0 || idx >= s.n {
+		return
+	}
+	return selectSample(ctx, s.source, idx)
+}
+
+func (s *TakeSource[E]) Weight(ctx context.Context, idx int) (float64, bool, error) {
+	if idx < 0 || idx >= s.n {
+		return 0, false, nil
+	}
+	return weight(ctx, s.source, idx)
+}
+
+// SkipSource selects elements of the source following n first ones
+type SkipSource[E any] struct {
+	source Source[E]
+	n      int
+}
+
+func NewSkipSource[E any](source Source[E], n int) *SkipSource[E] {
+	return &SkipSource[E]{
+		source: source,
+		n:      max(0, n),
+	}
+}
+
+func (s *SkipSource[E]) Count(ctx context.Context) (int, error) {
+	var count, err = s.source.Count(ctx)
+	return max(0, count-s.n), err
+}
+
+func (s *SkipSource[E]) Select(ctx context.Context, idx int) (e E, err error) {
+	if idx < 0 {
+		return
+	}
+	return s.source.Select(ctx, s.n+idx)
+}
+
+func (s *SkipSource[E]) selectWeighted(ctx context.Context, idx int) (sample Sample[E], err error) {
+	if idx < 0 {
+		return
+	}
+	return selectSample(ctx, s.source, s.n+idx)
+}
+
+func (s *SkipSource[E]) Weight(ctx context.Context, idx int) (float64, bool, error) {
+	if idx < 0 {
+		return 0, false, nil
+	}
+	return weight(ctx, s.source, s.n+idx)
+}
+
+// RepeatSource repeats elements of the source given number of times
+type RepeatSource[E any] struct {
+	source Source[E]
+	times  int
+}
+
+func NewRepeatSource[E any](source Source[E], times int) *RepeatSource[E] {
+	return &RepeatSource[E]{
+		source: source,
+		times:  max(0, times),
+	}
+}
+
+func (s *RepeatSource[E]) Count(ctx context.Context) (int, error) {
+	var count, err = s.source.Count(ctx)
+	return count * s.times, err
+}
+
+// local returns index of the element within the source or -1 if the index is out of range
+func (s *RepeatSource[E]) local(ctx context.Context, idx int) (int, error) {
+	var count, err = s.source.Count(ctx)
+	if err != nil || idx < 0 || idx >= count*s.times {
+		return -1, err
+	}
+	return idx % count, nil
+}
+
+func (s *RepeatSource[E]) Select(ctx context.Context, idx int) (e E, err error) {
+	if idx, err = s.local(ctx, idx); err != nil || idx < 0 {
+		return
+	}
+	return s.source.Select(ctx, idx)
+}
+
+func (s *RepeatSource[E]) selectWeighted(ctx context.Context, idx int) (sample Sample[E], err error) {
+	if idx, err = s.local(ctx, idx); err != nil || idx < 0 {
+		return
+	}
+	return selectSample(ctx, s.source, idx)
+}
+
+func (s *RepeatSource[E]) Weight(ctx context.Context, idx int) (float64, bool, error) {
+	var local, err = s.local(ctx, idx)
+	if err != nil || local < 0 {
+		return 0, false, err
+	}
+	return weight(ctx, s.source, local)
+}
+
+// CacheSource keeps recently selected elements of the source together with their weights, e.g. decoded records
+// of a file, and evicts the least recently used ones when the capacity is exceeded
+type CacheSource[E any] struct {
+	source   Source[E]
+	capacity int
+
+	mu       sync.Mutex
+	elements map[int]*list.Element
+	recent   *list.List
+}
+
+type cached[E any] struct {
+	idx    int
+	sample Sample[E]
+}
+
+func NewCacheSource[E any](source Source[E], capacity int) *CacheSource[E] {
+	return &CacheSource[E]{
+		source:   source,
+		capacity: max(1, capacity),
+		elements: make(map[int]*list.Element),
+		recent:   list.New(),
+	}
+}
+
+func (s *CacheSource[E]) Count(ctx context.Context) (int, error) {
+	return s.source.Count(ctx)
+}
+
+func (s *CacheSource[E]) Select(ctx context.Context, idx int) (e E, err error) {
+	var sample Sample[E]
+	sample, err = s.selectWeighted(ctx, idx)
+	return sample.Value, err
+}
+
+func (s *CacheSource[E]) selectWeighted(ctx context.Context, idx int) (sample Sample[E], err error) {
+	s.mu.Lock()
+	if element, found := s.elements[idx]; found {
+		s.recent.MoveToFront(element)
+		s.mu.Unlock()
+		return element.Value.(cached[E]).sample, nil
+	}
+	s.mu.Unlock()
+
+	if sample, err = selectSample(ctx, s.source, idx); err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, found := s.elements[idx]; !found {
+		s.elements[idx] = s.recent.PushFront(cached[E]{idx: idx, sample: sample})
+	}
+	for s.recent.Len() > s.capacity {
+		delete(s.elements, s.recent.Remove(s.recent.Back()).(cached[E]).idx)
+	}
+	return
+}
+
+func (s *CacheSource[E]) Weight(ctx context.Context, idx int) (float64, bool, error) {
+	return weight(ctx, s.source, idx)
+}
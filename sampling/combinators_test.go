@@ -0,0 +1,100 @@
+package sampling
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+// countingSource counts selections of elements of the slice
+type countingSource struct {
+	*SliceSource[[]int, int]
+	selections int
+}
+
+func (s *countingSource) Select(ctx context.Context, idx int) (int, error) {
+	s.selections++
+	return s.SliceSource.Select(ctx, idx)
+}
+
+func TestMapSource(t *testing.T) {
+	var source = NewMapSource[int, string](NewSliceSource([]int{1, 2}), func(e int) (string, error) {
+		return strconv.Itoa(e * 10), nil
+	})
+	assert.Equal(t, []string{"10", "20"}, values(t, []Source[string]{source})[0])
+
+	var err = errors.New("error")
+	_, actual := NewMapSource[int, int](NewSliceSource([]int{1}), func(int) (int, error) { return 0, err }).Select(context.TODO(), 0)
+	assert.ErrorIs(t, actual, err)
+}
+
+func TestFilterSource(t *testing.T) {
+	var (
+		counting    = &countingSource{SliceSource: NewSliceSource([]int{1, 2, 3, 4, 5, 6})}
+		weighted, _ = NewWeightedSource[int](counting, []float64{1, 2, 3, 4, 5, 6})
+		source      = NewFilterSource[int](weighted, func(e int) bool { return e%2 == 0 })
+	)
+	assert.Equal(t, []int{2, 4, 6}, values(t, []Source[int]{source})[0])
+	assert.Equal(t, 9, counting.selections)
+
+	var w, ok, err = source.Weight(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 4.0, w)
+
+	var ctx, cancel = context.WithCancel(context.TODO())
+	cancel()
+	_, err = NewFilterSource[int](counting, func(int) bool { return true }).Count(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestConcatSource(t *testing.T) {
+	var (
+		weighted, _ = NewWeightedSource[int](NewSliceSource([]int{3}), []float64{2})
+		source      = NewConcatSource[int](NewSliceSource([]int{1, 2}), NewSliceSource([]int{}), weighted)
+	)
+	assert.Equal(t, []int{1, 2, 3}, values(t, []Source[int]{source})[0])
+	var w, ok, err = source.Weight(context.TODO(), 2)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, w)
+	_, ok, err = source.Weight(context.TODO(), 0)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	var e int
+	e, err = source.Select(context.TODO(), 3)
+	assert.NoError(t, err)
+	assert.Zero(t, e)
+}
+
+func TestTakeAndSkipSource(t *testing.T) {
+	var source = NewSliceSource([]int{1, 2, 3, 4})
+	assert.Equal(t, [][]int{{1, 2}, {1, 2, 3, 4}, {3, 4}, nil}, values(t, []Source[int]{
+		NewTakeSource[int](source, 2),
+		NewTakeSource[int](source, 10),
+		NewSkipSource[int](source, 2),
+		NewSkipSource[int](source, 10),
+	}))
+}
+
+func TestRepeatSource(t *testing.T) {
+	var source = NewRepeatSource[int](NewSliceSource([]int{1, 2}), 3)
+	assert.Equal(t, []int{1, 2, 1, 2, 1, 2}, values(t, []Source[int]{source})[0])
+}
+
+func TestCacheSource(t *testing.T) {
+	var (
+		counting = &countingSource{SliceSource: NewSliceSource([]int{1, 2, 3})}
+		source   = NewCacheSource[int](counting, 2)
+	)
+	for _, idx := range []int{0, 1, 0, 2, 0, 1} {
+		var e, err = source.Select(context.TODO(), idx)
+		assert.NoError(t, err)
+		assert.Equal(t, idx+1, e)
+	}
+	// 1 is evicted by 2 and selected again
+	assert.Equal(t, 4, counting.selections)
+}
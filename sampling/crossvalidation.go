@@ -0,0 +1,139 @@
+package sampling
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"math"
+	"sync"
+)
+
+// Model is trained on the training set of a fold and scored on its validation set
+type Model[E any] interface {
+	Train(ctx context.Context, source Source[E]) error
+	// Score returns values of metrics keyed by their names
+	Score(ctx context.Context, source Source[E]) (map[string]float64, error)
+}
+
+// ModelFactory creates fresh model for each fold
+type ModelFactory[E any] func() (Model[E], error)
+
+// CrossValidation holds scores of each fold and their aggregates
+type CrossValidation struct {
+	// Scores contains chosen metrics of each fold
+	Scores []map[string]float64
+	// Mean contains mean of each chosen metric over folds
+	Mean map[string]float64
+	// StdDev contains standard deviation of each chosen metric over folds
+	StdDev map[string]float64
+}
+
+type crossValidationConfig struct {
+	concurrency int
+}
+
+type CrossValidationOption func(*crossValidationConfig)
+
+// WithConcurrency sets number of folds evaluated concurrently. The default is 1
+func WithConcurrency(n int) CrossValidationOption {
+	return func(c *crossValidationConfig) {
+		c.concurrency = max(1, n)
+	}
+}
+
+// CrossValidate trains and scores a model created by the factory on each fold and aggregates chosen metrics.
+// It stops at the first error
+func CrossValidate[E any](ctx context.Context, factory ModelFactory[E], folds []Fold[E], metrics []string, opts ...CrossValidationOption) (result CrossValidation, err error) {
+	var config = crossValidationConfig{concurrency: 1}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	var (
+		wg      sync.WaitGroup
+		threads = min(config.concurrency, len(folds))
+		ch      = make(chan int)
+		errs    = make([]error, len(folds))
+		cancel  context.CancelFunc
+	)
+	ctx, cancel = context.WithCancel(ctx)
+	defer cancel()
+
+	result.Scores = make([]map[string]float64, len(folds))
+	for range threads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ch {
+				if result.Scores[i], errs[i] = evaluate(ctx, factory, folds[i], metrics); errs[i] != nil {
+					cancel()
+				}
+			}
+		}()
+	}
+	for i := range folds {
+		select {
+		case <-ctx.Done():
+		case ch <- i:
+			continue
+		}
+		break
+	}
+	close(ch)
+	wg.Wait()
+
+	for i := range folds {
+		if errs[i] != nil && !errors.Is(errs[i], context.Canceled) {
+			err = errors.WithMessagef(errs[i], "fold %d", i)
+			return
+		}
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	result.Mean, result.StdDev = aggregate(result.Scores, metrics)
+	return
+}
+
+func evaluate[E any](ctx context.Context, factory ModelFactory[E], fold Fold[E], metrics []string) (scores map[string]float64, err error) {
+	var model Model[E]
+	if model, err = factory(); err != nil {
+		return
+	}
+	if err = model.Train(ctx, fold.Train); err != nil {
+		return
+	}
+	var all map[string]float64
+	if all, err = model.Score(ctx, fold.Validation); err != nil {
+		return
+	}
+	scores = make(map[string]float64, len(metrics))
+	for _, name := range metrics {
+		var value, found = all[name]
+		if !found {
+			err = errors.WithMessagef(errors.UnknownNameError, "metric=%s", name)
+			return
+		}
+		scores[name] = value
+	}
+	return
+}
+
+// aggregate computes mean and population standard deviation of each metric
+func aggregate(scores []map[string]float64, metrics []string) (mean, stdDev map[string]float64) {
+	mean = make(map[string]float64, len(metrics))
+	stdDev = make(map[string]float64, len(metrics))
+	if len(scores) == 0 {
+		return
+	}
+	var n = float64(len(scores))
+	for _, name := range metrics {
+		for _, s := range scores {
+			mean[name] += s[name] / n
+		}
+		var variance float64
+		for _, s := range scores {
+			variance += (s[name] - mean[name]) * (s[name] - mean[name]) / n
+		}
+		stdDev[name] = math.Sqrt(variance)
+	}
+	return
+}
@@ -0,0 +1,272 @@
+package sampling
+
+import (
+	"context"
+	"encoding/csv"
+	"github.com/publiczny81/ml/errors"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Converter converts value of the column into number, e.g. category into its code
+type Converter func(value string) (float64, error)
+
+type csvConfig struct {
+	comma      rune
+	header     bool
+	features   []string
+	targets    []string
+	missing    []string
+	fill       float64
+	converters map[string]Converter
+}
+
+type CSVOption func(*csvConfig)
+
+// WithComma sets the separator of fields. The default is ','
+func WithComma(comma rune) CSVOption {
+	return func(c *csvConfig) {
+		c.comma = comma
+	}
+}
+
+// WithHeader treats the first row as names of columns
+func WithHeader() CSVOption {
+	return func(c *csvConfig) {
+		c.header = true
+	}
+}
+
+// WithFeatures selects columns of features in given order. Columns are referenced by names from the header or,
+// without the header, by their zero-based index, e.g. "0". By default all columns which are not targets are features
+func WithFeatures(columns ...string) CSVOption {
+	return func(c *csvConfig) {
+		c.features = columns
+	}
+}
+
+// WithTargets selects columns of targets in given order. Columns are referenced the same way as in WithFeatures
+func WithTargets(columns ...string) CSVOption {
+	return func(c *csvConfig) {
+		c.targets = columns
+	}
+}
+
+// WithMissing sets markers of missing values and the value which replaces them. By default empty fields and "NA"
+// are missing and replaced with NaN
+func WithMissing(value float64, markers ...string) CSVOption {
+	return func(c *csvConfig) {
+		c.fill = value
+		c.missing = markers
+	}
+}
+
+// WithConverter sets converter of the column used instead of parsing the number
+func WithConverter(column string, converter Converter) CSVOption {
+	return func(c *csvConfig) {
+		c.converters[column] = converter
+	}
+}
+
+// CSVSource is a Source of rows of CSV or TSV file. Each element is a pair of vectors where the first one holds
+// features and the second one targets of the row. The offsets of rows are indexed on the first scan of the file,
+// so selection of a row reads only that row
+type CSVSource struct {
+	file    *os.File
+	config  csvConfig
+	names   []string
+	columns [2][]int
+	data    int64
+
+	mu      sync.Mutex
+	indexed bool
+	offsets []int64
+	lines   []int
+}
+
+// NewCSVSource opens CSV file. The file is closed with Close
+func NewCSVSource(path string, opts ...CSVOption) (s *CSVSource, err error) {
+	var config = csvConfig{
+		comma:      ',',
+		missing:    []string{"", "NA"},
+		fill:       math.NaN(),
+		converters: make(map[string]Converter),
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	var file *os.File
+	if file, err = os.Open(path); err != nil {
+		return
+	}
+	s = &CSVSource{
+		file:   file,
+		config: config,
+	}
+	if err = s.init(); err != nil {
+		_ = file.Close()
+		s = nil
+	}
+	return
+}
+
+// NewTSVSource opens file with fields separated by tabs
+func NewTSVSource(path string, opts ...CSVOption) (*CSVSource, error) {
+	return NewCSVSource(path, append([]CSVOption{WithComma('\t')}, opts...)...)
+}
+
+// init reads names of columns and resolves selected features and targets
+func (s *CSVSource) init() (err error) {
+	var (
+		reader = s.reader(0, math.MaxInt64)
+		record []string
+	)
+	if record, err = reader.Read(); err == io.EOF {
+		return errors.WithMessage(errors.InvalidParameterValueError, "NewCSVSource: file is empty")
+	} else if err != nil {
+		return
+	}
+	if s.config.header {
+		s.names = slices.Clone(record)
+		s.data = reader.InputOffset()
+	} else {
+		for i := range record {
+			s.names = append(s.names, strconv.Itoa(i))
+		}
+	}
+	if s.columns[1], err = s.resolve(s.config.targets); err != nil {
+		return
+	}
+	if s.config.features != nil {
+		s.columns[0], err = s.resolve(s.config.features)
+		return
+	}
+	for i := range s.names {
+		if !slices.Contains(s.columns[1], i) {
+			s.columns[0] = append(s.columns[0], i)
+		}
+	}
+	return
+}
+
+func (s *CSVSource) resolve(names []string) (columns []int, err error) {
+	for _, name := range names {
+		var i = slices.Index(s.names, name)
+		if i < 0 {
+			return nil, errors.WithMessagef(errors.UnknownNameError, "column=%s", name)
+		}
+		columns = append(columns, i)
+	}
+	return
+}
+
+func (s *CSVSource) reader(from, to int64) (reader *csv.Reader) {
+	reader = csv.NewReader(io.NewSectionReader(s.file, from, to-from))
+	reader.Comma = s.config.comma
+	reader.ReuseRecord = true
+	reader.FieldsPerRecord = -1
+	return
+}
+
+// index scans the file once and records offsets and lines of rows
+func (s *CSVSource) index(ctx context.Context) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.indexed {
+		return
+	}
+	var (
+		reader  = s.reader(s.data, math.MaxInt64)
+		offsets []int64
+		lines   []int
+		line    = 1
+	)
+	if s.config.header {
+		line = 2
+	}
+	for {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		var offset = s.data + reader.InputOffset()
+		if _, err = reader.Read(); err == io.EOF {
+			offsets = append(offsets, offset)
+			break
+		} else if err != nil {
+			var parseError *csv.ParseError
+			if errors.As(err, &parseError) {
+				parseError.StartLine += line - 1
+				parseError.Line += line - 1
+			}
+			return
+		}
+		var row, _ = reader.FieldPos(0)
+		offsets = append(offsets, offset)
+		lines = append(lines, row+line-1)
+	}
+	s.offsets, s.lines, s.indexed = offsets, lines, true
+	return nil
+}
+
+// Names returns names of all columns of the file
+func (s *CSVSource) Names() []string {
+	return slices.Clone(s.names)
+}
+
+func (s *CSVSource) Count(ctx context.Context) (int, error) {
+	if err := s.index(ctx); err != nil {
+		return 0, err
+	}
+	return len(s.lines), nil
+}
+
+func (s *CSVSource) Select(ctx context.Context, idx int) (e [][]float64, err error) {
+	if err = s.index(ctx); err != nil {
+		return
+	}
+	if idx < 0 || idx >= len(s.lines) {
+		return
+	}
+	var record []string
+	if record, err = s.reader(s.offsets[idx], s.offsets[idx+1]).Read(); err != nil {
+		return nil, errors.WithMessagef(err, "line=%d", s.lines[idx])
+	}
+	e = make([][]float64, 2)
+	for i, columns := range s.columns {
+		e[i] = make([]float64, len(columns))
+		for j, column := range columns {
+			if column >= len(record) {
+				return nil, errors.WithMessagef(errors.InvalidParameterValueError, "line=%d, column=%s: missing field", s.lines[idx], s.names[column])
+			}
+			if e[i][j], err = s.convert(column, record[column]); err != nil {
+				return nil, errors.WithMessagef(err, "line=%d, column=%s", s.lines[idx], s.names[column])
+			}
+		}
+	}
+	return
+}
+
+func (s *CSVSource) convert(column int, value string) (float64, error) {
+	if converter, found := s.config.converters[s.names[column]]; found {
+		return converter(value)
+	}
+	value = strings.TrimSpace(value)
+	if slices.Contains(s.config.missing, value) {
+		return s.config.fill, nil
+	}
+	var f, err = strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, errors.WithMessagef(errors.InvalidParameterValueError, "value=%q", value)
+	}
+	return f, nil
+}
+
+// Close closes the file
+func (s *CSVSource) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,91 @@
+package sampling
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, content string) string {
+	var path = filepath.Join(t.TempDir(), "data")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestCSVSource(t *testing.T) {
+	var path = writeFile(t, "x,y,label\n1,2,0\n\"3\",NA,1\n\n5.5,-1,2\n")
+	var source, err = NewCSVSource(path, WithHeader(), WithTargets("label"))
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, source.Close())
+	}()
+	assert.Equal(t, []string{"x", "y", "label"}, source.Names())
+
+	var count int
+	count, err = source.Count(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	var e [][]float64
+	e, err = source.Select(context.TODO(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{{5.5, -1}, {2}}, e)
+
+	e, err = source.Select(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3.0, e[0][0])
+	assert.True(t, math.IsNaN(e[0][1]))
+
+	e, err = source.Select(context.TODO(), 3)
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+}
+
+func TestCSVSourceWithOptions(t *testing.T) {
+	var path = writeFile(t, "a\t1\t?\nb\t2\t3\n")
+	var source, err = NewTSVSource(path,
+		WithFeatures("2", "1"),
+		WithTargets("0"),
+		WithMissing(0, "?"),
+		WithConverter("0", func(value string) (float64, error) {
+			return float64(value[0] - 'a'), nil
+		}))
+	assert.NoError(t, err)
+	defer func() {
+		_ = source.Close()
+	}()
+
+	var values = values(t, []Source[[][]float64]{source})[0]
+	assert.Equal(t, [][][]float64{{{0, 1}, {0}}, {{3, 2}, {1}}}, values)
+}
+
+func TestCSVSourceErrors(t *testing.T) {
+	var _, err = NewCSVSource(writeFile(t, ""))
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+
+	_, err = NewCSVSource(writeFile(t, "x,y\n1,2\n"), WithHeader(), WithTargets("z"))
+	assert.ErrorIs(t, err, errors.UnknownNameError)
+
+	var source *CSVSource
+	source, err = NewCSVSource(writeFile(t, "x,y\n1,2\n3,abc\n4\n"), WithHeader())
+	assert.NoError(t, err)
+	defer func() {
+		_ = source.Close()
+	}()
+	_, err = source.Select(context.TODO(), 1)
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+	assert.ErrorContains(t, err, "line=3, column=y")
+
+	_, err = source.Select(context.TODO(), 2)
+	assert.ErrorContains(t, err, "line=4, column=y: missing field")
+
+	source, err = NewCSVSource(writeFile(t, "x,y\n1,2\n3,\"4\n"), WithHeader())
+	assert.NoError(t, err)
+	_, err = source.Count(context.TODO())
+	assert.ErrorContains(t, err, "line 3")
+	_ = source.Close()
+}
@@ -0,0 +1,197 @@
+package sampling
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"slices"
+)
+
+// Example is a labelled sample used in supervised learning
+type Example struct {
+	Features []float64
+	Target   []float64
+	// Weight scales the contribution of the example to training when the example is Weighted
+	Weight float64
+	// Weighted tells whether Weight is given, so an explicit zero weight is distinguished from a missing one
+	Weighted bool
+	// ID identifies the example, e.g. the record it comes from
+	ID string
+}
+
+// weight is used by strategies to carry the weight of the example to the sample
+func (e Example) weight() (float64, bool) {
+	return e.Weight, e.Weighted
+}
+
+// Pair returns features and target as the pair of vectors consumed by trainers
+func (e Example) Pair() [][]float64 {
+	return [][]float64{e.Features, e.Target}
+}
+
+// Class returns the class of the example, i.e. the value of single target or the index of the largest value of
+// one-hot encoded target. It is meant as label of SplitSetStratified and StratifiedKFold
+func (e Example) Class() int {
+	if len(e.Target) == 1 {
+		return int(e.Target[0])
+	}
+	var class int
+	for i, v := range e.Target {
+		if v > e.Target[class] {
+			class = i
+		}
+	}
+	return class
+}
+
+// weight returns weight of the element at given index if the source is Weighted
+func weight[E any](ctx context.Context, source Source[E], idx int) (float64, bool, error) {
+	if w, ok := source.(Weighted); ok {
+		return w.Weight(ctx, idx)
+	}
+	return 0, false, nil
+}
+
+// ZipSource pairs features and targets of two sources of equal size into examples
+type ZipSource struct {
+	features Source[[]float64]
+	targets  Source[[]float64]
+}
+
+func NewZipSource(features, targets Source[[]float64]) *ZipSource {
+	return &ZipSource{
+		features: features,
+		targets:  targets,
+	}
+}
+
+func (s *ZipSource) Count(ctx context.Context) (count int, err error) {
+	var targets int
+	if count, err = s.features.Count(ctx); err != nil {
+		return
+	}
+	if targets, err = s.targets.Count(ctx); err != nil {
+		return
+	}
+	if count != targets {
+		return 0, errors.WithMessagef(errors.UnmatchedSizeOfVectorsError, "ZipSource: features=%d, targets=%d", count, targets)
+	}
+	return
+}
+
+func (s *ZipSource) Select(ctx context.Context, idx int) (e Example, err error) {
+	if e.Features, err = s.features.Select(ctx, idx); err != nil {
+		return
+	}
+	if e.Target, err = s.targets.Select(ctx, idx); err != nil {
+		return
+	}
+	e.Weight, e.Weighted, err = weight(ctx, s.features, idx)
+	return
+}
+
+// ColumnSource splits rows of the source into features and target columns
+type ColumnSource struct {
+	source  Source[[]float64]
+	targets []int
+}
+
+// NewColumnSource creates source whose examples have targets taken from given columns in given order.
+// The remaining columns are features
+func NewColumnSource(source Source[[]float64], targets ...int) *ColumnSource {
+	return &ColumnSource{
+		source:  source,
+		targets: targets,
+	}
+}
+
+func (s *ColumnSource) Count(ctx context.Context) (int, error) {
+	return s.source.Count(ctx)
+}
+
+func (s *ColumnSource) Select(ctx context.Context, idx int) (e Example, err error) {
+	var row []float64
+	if row, err = s.source.Select(ctx, idx); err != nil {
+		return
+	}
+	e.Features = make([]float64, 0, len(row))
+	for i, v := range row {
+		if !slices.Contains(s.targets, i) {
+			e.Features = append(e.Features, v)
+		}
+	}
+	e.Target = make([]float64, len(s.targets))
+	for i, column := range s.targets {
+		if column < 0 || column >= len(row) {
+			return Example{}, errors.WithMessagef(errors.InvalidParameterValueError, "ColumnSource: column=%d, len(row)=%d", column, len(row))
+		}
+		e.Target[i] = row[column]
+	}
+	e.Weight, e.Weighted, err = weight(ctx, s.source, idx)
+	return
+}
+
+// ExampleSource converts pairs of vectors, e.g. rows of CSVSource, into examples
+type ExampleSource struct {
+	source Source[[][]float64]
+}
+
+func NewExampleSource(source Source[[][]float64]) *ExampleSource {
+	return &ExampleSource{
+		source: source,
+	}
+}
+
+func (s *ExampleSource) Count(ctx context.Context) (int, error) {
+	return s.source.Count(ctx)
+}
+
+func (s *ExampleSource) Select(ctx context.Context, idx int) (e Example, err error) {
+	var pair [][]float64
+	if pair, err = s.source.Select(ctx, idx); err != nil || pair == nil {
+		return
+	}
+	if len(pair) != 2 {
+		return Example{}, errors.WithMessagef(errors.InvalidParameterValueError, "ExampleSource: len(pair)=%d", len(pair))
+	}
+	e.Features, e.Target = pair[0], pair[1]
+	e.Weight, e.Weighted, err = weight(ctx, s.source, idx)
+	return
+}
+
+// PairSource converts examples into pairs of features and target consumed by trainers. Weights of examples
+// are carried to samples, so each example is selected once
+type PairSource struct {
+	source Source[Example]
+}
+
+func NewPairSource(source Source[Example]) *PairSource {
+	return &PairSource{
+		source: source,
+	}
+}
+
+func (s *PairSource) Count(ctx context.Context) (int, error) {
+	return s.source.Count(ctx)
+}
+
+func (s *PairSource) Select(ctx context.Context, idx int) (e [][]float64, err error) {
+	var example Example
+	if example, err = s.source.Select(ctx, idx); err != nil {
+		return
+	}
+	return example.Pair(), nil
+}
+
+func (s *PairSource) selectWeighted(ctx context.Context, idx int) (pair Sample[[][]float64], err error) {
+	var example Sample[Example]
+	if example, err = selectSample(ctx, s.source, idx); err != nil {
+		return
+	}
+	return Sample[[][]float64]{Value: example.Value.Pair(), Weight: example.Weight, Weighted: example.Weighted}, nil
+}
+
+// Weight returns weight of the example given by the source or by the example itself
+func (s *PairSource) Weight(ctx context.Context, idx int) (float64, bool, error) {
+	var pair, err = s.selectWeighted(ctx, idx)
+	return pair.Weight, pair.Weighted, err
+}
@@ -0,0 +1,120 @@
+package sampling
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestZipSource(t *testing.T) {
+	var (
+		features, _ = NewWeightedSource[[]float64](NewSliceSource([][]float64{{1, 2}, {3, 4}}), []float64{0.5, 2})
+		source      = NewZipSource(features, NewSliceSource([][]float64{{0}, {1}}))
+	)
+	assert.Equal(t, []Example{
+		{Features: []float64{1, 2}, Target: []float64{0}, Weight: 0.5, Weighted: true},
+		{Features: []float64{3, 4}, Target: []float64{1}, Weight: 2, Weighted: true},
+	}, values(t, []Source[Example]{source})[0])
+
+	_, err := NewZipSource(features, NewSliceSource([][]float64{{0}})).Count(context.TODO())
+	assert.ErrorIs(t, err, errors.UnmatchedSizeOfVectorsError)
+}
+
+func TestColumnSource(t *testing.T) {
+	var source = NewColumnSource(NewSliceSource([][]float64{{1, 2, 3, 4}}), 3, 0)
+	var e, err = source.Select(context.TODO(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, Example{Features: []float64{2, 3}, Target: []float64{4, 1}}, e)
+
+	_, err = NewColumnSource(NewSliceSource([][]float64{{1}}), 1).Select(context.TODO(), 0)
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+}
+
+func TestExampleAndPairSource(t *testing.T) {
+	var (
+		pairs    = NewSliceSource([][][]float64{{{1}, {0, 1}}, {{2}, {1, 0}}})
+		examples = NewExampleSource(pairs)
+	)
+	var e, err = examples.Select(context.TODO(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, Example{Features: []float64{1}, Target: []float64{0, 1}}, e)
+	assert.Equal(t, 1, e.Class())
+	assert.Equal(t, 2, Example{Target: []float64{2}}.Class())
+
+	var source = NewPairSource(NewSliceSource([]Example{{Features: []float64{1}, Target: []float64{2}, Weight: 3, Weighted: true}}))
+	var samples []Sample[[][]float64]
+	for sample := range new(SystematicalStrategy[[][]float64]).Samples(context.TODO(), source) {
+		samples = append(samples, sample)
+	}
+	assert.Equal(t, []Sample[[][]float64]{WeightedValueOf([][]float64{{1}, {2}}, 3)}, samples)
+}
+
+func TestStrategyCarriesWeightOfExample(t *testing.T) {
+	var source = NewSliceSource([]Example{{ID: "a", Weight: 2, Weighted: true}, {ID: "b"}, {ID: "c", Weighted: true}})
+	var samples []Sample[Example]
+	for sample := range new(SystematicalStrategy[Example]).Samples(context.TODO(), source) {
+		samples = append(samples, sample)
+	}
+	assert.Equal(t, 2.0, samples[0].EffectiveWeight())
+	assert.Equal(t, 1.0, samples[1].EffectiveWeight())
+	assert.Equal(t, 0.0, samples[2].EffectiveWeight())
+}
+
+func TestViewsCarryWeightOfExample(t *testing.T) {
+	var (
+		source = NewSliceSource([]Example{{ID: "a", Weight: 2, Weighted: true}, {ID: "b"}, {ID: "c", Weight: 0.5, Weighted: true}})
+		sets   []Source[Example]
+		weight = func(source Source[Example]) (weights []float64) {
+			for sample := range new(SystematicalStrategy[Example]).Samples(context.TODO(), source) {
+				assert.NoError(t, sample.Error)
+				weights = append(weights, sample.EffectiveWeight())
+			}
+			return
+		}
+	)
+	var split, err = SplitSet[Example](source, 0.4, 0.3, 0.3)
+	assert.NoError(t, err)
+	var weights []float64
+	for _, set := range split {
+		weights = append(weights, weight(set)...)
+	}
+	assert.Equal(t, []float64{2, 1, 0.5}, weights)
+
+	folds, err := KFold[Example](source, 3, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1, 0.5}, weight(folds[0].Train))
+	assert.Equal(t, []float64{2}, weight(folds[0].Validation))
+
+	var cache = NewCacheSource[Example](source, 2)
+	assert.Equal(t, []float64{2, 1, 0.5}, weight(cache))
+	assert.Equal(t, []float64{2, 1, 0.5}, weight(cache))
+
+	sets = append(sets, NewMapSource(source, func(e Example) (Example, error) { return e, nil }), NewTakeSource[Example](source, 3))
+	for _, set := range sets {
+		assert.Equal(t, []float64{2, 1, 0.5}, weight(set))
+	}
+}
+
+// countingExamples counts selections of examples
+type countingExamples struct {
+	*SliceSource[[]Example, Example]
+	selections int
+}
+
+func (s *countingExamples) Select(ctx context.Context, idx int) (Example, error) {
+	s.selections++
+	return s.SliceSource.Select(ctx, idx)
+}
+
+func TestPairSourceSelectsExampleOnce(t *testing.T) {
+	var (
+		counting = &countingExamples{SliceSource: NewSliceSource([]Example{{Weight: 2, Weighted: true}, {}})}
+		weights  []float64
+	)
+	for sample := range new(SystematicalStrategy[[][]float64]).Samples(context.TODO(), NewPairSource(counting)) {
+		weights = append(weights, sample.EffectiveWeight())
+	}
+	assert.Equal(t, []float64{2, 1}, weights)
+	assert.Equal(t, 2, counting.selections)
+}
@@ -0,0 +1,126 @@
+package sampling
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+)
+
+// Fold is a pair of training and validation sets. Both are views of the source which do not copy elements
+type Fold[E any] struct {
+	Train      Source[E]
+	Validation Source[E]
+}
+
+// KFold divides source into k folds of almost equal size. Each fold is used once as validation set while
+// the rest is used for training. Elements are shuffled when rand is not nil
+func KFold[E any](source Source[E], k int, rand Rand) (folds []Fold[E], err error) {
+	var count int
+	if count, err = countFolds(source, k); err != nil {
+		return
+	}
+	var indices = make([]int, count)
+	for i := range indices {
+		indices[i] = i
+	}
+	if rand != nil {
+		shuffle(rand, indices)
+	}
+	var (
+		assigned = make([][]int, k)
+		start    int
+	)
+	for i := range k {
+		var end = start + count/k
+		if i < count%k {
+			end++
+		}
+		assigned[i] = indices[start:end]
+		start = end
+	}
+	return newFolds(source, assigned), nil
+}
+
+// StratifiedKFold divides source into k folds keeping proportions of labels in each fold.
+// Elements of each label are shuffled when rand is not nil
+func StratifiedKFold[E any, L comparable](source Source[E], k int, rand Rand, label func(E) L) (folds []Fold[E], err error) {
+	if _, err = countFolds(source, k); err != nil {
+		return
+	}
+	var groups [][]int
+	if groups, err = group(source, label); err != nil {
+		return
+	}
+	var (
+		assigned = make([][]int, k)
+		position int
+	)
+	for _, g := range groups {
+		if rand != nil {
+			shuffle(rand, g)
+		}
+		for _, idx := range g {
+			assigned[position%k] = append(assigned[position%k], idx)
+			position++
+		}
+	}
+	return newFolds(source, assigned), nil
+}
+
+// LeaveOneOut creates as many folds as the source has elements. Each element is used once as validation set
+func LeaveOneOut[E any](source Source[E]) (folds []Fold[E], err error) {
+	var count int
+	if count, err = source.Count(context.Background()); err != nil {
+		return
+	}
+	return KFold(source, count, nil)
+}
+
+// TimeSeriesSplit creates forward-chaining folds for ordered source. The source is divided into splits+1 parts
+// and fold i is trained on parts up to i and validated on part i+1, so validation elements always follow
+// training ones
+func TimeSeriesSplit[E any](source Source[E], splits int) (folds []Fold[E], err error) {
+	var count int
+	if count, err = source.Count(context.Background()); err != nil {
+		return
+	}
+	if splits < 1 || count < splits+1 {
+		err = errors.WithMessagef(errors.InvalidParameterValueError, "TimeSeriesSplit: splits=%d, count=%d", splits, count)
+		return
+	}
+	var size = count / (splits + 1)
+	for i := range splits {
+		var end = count - (splits-i-1)*size
+		folds = append(folds, Fold[E]{
+			Train:      MustNewLimitedSource(source, 0, end-size),
+			Validation: MustNewLimitedSource(source, end-size, end),
+		})
+	}
+	return
+}
+
+func countFolds[E any](source Source[E], k int) (count int, err error) {
+	if count, err = source.Count(context.Background()); err != nil {
+		return
+	}
+	if k < 2 || k > count {
+		err = errors.WithMessagef(errors.InvalidParameterValueError, "k=%d, count=%d", k, count)
+	}
+	return
+}
+
+// newFolds creates folds validated on each group of indices and trained on the others
+func newFolds[E any](source Source[E], assigned [][]int) (folds []Fold[E]) {
+	for i, validation := range assigned {
+		var train []int
+		for j, other := range assigned {
+			if j != i {
+				train = append(train, other...)
+			}
+		}
+		folds = append(folds, Fold[E]{
+			Train:      NewIndexedSource(source, train),
+			Validation: NewIndexedSource(source, validation),
+		})
+	}
+	return
+}
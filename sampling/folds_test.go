@@ -0,0 +1,169 @@
+package sampling
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/publiczny81/ml/utils"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestKFold(t *testing.T) {
+	var source = NewSliceSource([]int{1, 2, 3, 4, 5, 6, 7})
+	var folds, err = KFold[int](source, 3, nil)
+	assert.NoError(t, err)
+	assert.Len(t, folds, 3)
+
+	var expected = [][2][]int{
+		{{4, 5, 6, 7}, {1, 2, 3}},
+		{{1, 2, 3, 6, 7}, {4, 5}},
+		{{1, 2, 3, 4, 5}, {6, 7}},
+	}
+	for i, fold := range folds {
+		var sets = values(t, []Source[int]{fold.Train, fold.Validation})
+		assert.Equal(t, expected[i][0], sets[0])
+		assert.Equal(t, expected[i][1], sets[1])
+	}
+
+	_, err = KFold[int](source, 1, nil)
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+	_, err = KFold[int](source, 8, nil)
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+}
+
+func TestKFoldShuffled(t *testing.T) {
+	var source = NewSliceSource([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	var folds, err = KFold[int](source, 5, utils.NewPCG(1))
+	assert.NoError(t, err)
+
+	var all []int
+	for _, fold := range folds {
+		var sets = values(t, []Source[int]{fold.Train, fold.Validation})
+		assert.Len(t, sets[0], 8)
+		assert.Len(t, sets[1], 2)
+		all = append(all, sets[1]...)
+	}
+	slices.Sort(all)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, all)
+}
+
+func TestStratifiedKFold(t *testing.T) {
+	var elements []int
+	for i := range 12 {
+		elements = append(elements, i)
+	}
+	var folds, err = StratifiedKFold[int](NewSliceSource(elements), 4, utils.NewPCG(1), func(e int) bool { return e < 4 })
+	assert.NoError(t, err)
+	assert.Len(t, folds, 4)
+
+	var all []int
+	for _, fold := range folds {
+		var validation = values(t, []Source[int]{fold.Validation})[0]
+		assert.Len(t, validation, 3)
+		var positive int
+		for _, e := range validation {
+			if e < 4 {
+				positive++
+			}
+		}
+		assert.Equal(t, 1, positive)
+		all = append(all, validation...)
+	}
+	slices.Sort(all)
+	assert.Equal(t, elements, all)
+}
+
+func TestLeaveOneOut(t *testing.T) {
+	var folds, err = LeaveOneOut[int](NewSliceSource([]int{1, 2, 3}))
+	assert.NoError(t, err)
+	assert.Len(t, folds, 3)
+	for i, fold := range folds {
+		var sets = values(t, []Source[int]{fold.Train, fold.Validation})
+		assert.Len(t, sets[0], 2)
+		assert.Equal(t, []int{i + 1}, sets[1])
+	}
+}
+
+func TestTimeSeriesSplit(t *testing.T) {
+	var source = NewSliceSource([]int{1, 2, 3, 4, 5, 6, 7})
+	var folds, err = TimeSeriesSplit[int](source, 3)
+	assert.NoError(t, err)
+
+	var expected = [][2][]int{
+		{{1, 2, 3, 4}, {5}},
+		{{1, 2, 3, 4, 5}, {6}},
+		{{1, 2, 3, 4, 5, 6}, {7}},
+	}
+	assert.Len(t, folds, 3)
+	for i, fold := range folds {
+		var sets = values(t, []Source[int]{fold.Train, fold.Validation})
+		assert.Equal(t, expected[i][0], sets[0])
+		assert.Equal(t, expected[i][1], sets[1])
+	}
+
+	_, err = TimeSeriesSplit[int](source, 7)
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+}
+
+// meanModel predicts the mean of training elements and scores it with the mean absolute error
+type meanModel struct {
+	mean float64
+}
+
+func (m *meanModel) Train(ctx context.Context, source Source[float64]) error {
+	var count, err = source.Count(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range count {
+		var e, _ = source.Select(ctx, i)
+		m.mean += e / float64(count)
+	}
+	return nil
+}
+
+func (m *meanModel) Score(ctx context.Context, source Source[float64]) (map[string]float64, error) {
+	var count, err = source.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var mae float64
+	for i := range count {
+		var e, _ = source.Select(ctx, i)
+		mae += math.Abs(e-m.mean) / float64(count)
+	}
+	return map[string]float64{"mae": mae, "mean": m.mean}, nil
+}
+
+func TestCrossValidate(t *testing.T) {
+	var (
+		source     = NewSliceSource([]float64{1, 2, 3, 4})
+		folds, err = KFold[float64](source, 2, nil)
+		factory    = func() (Model[float64], error) { return new(meanModel), nil }
+	)
+	assert.NoError(t, err)
+
+	for _, concurrency := range []int{1, 2} {
+		var result CrossValidation
+		result, err = CrossValidate(context.TODO(), factory, folds, []string{"mae"}, WithConcurrency(concurrency))
+		assert.NoError(t, err)
+		assert.Equal(t, []map[string]float64{{"mae": 2}, {"mae": 2}}, result.Scores)
+		assert.Equal(t, map[string]float64{"mae": 2}, result.Mean)
+		assert.Equal(t, map[string]float64{"mae": 0}, result.StdDev)
+	}
+
+	var result CrossValidation
+	result, err = CrossValidate(context.TODO(), factory, folds, []string{"mean"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, result.Mean["mean"])
+	assert.Equal(t, 1.0, result.StdDev["mean"])
+
+	_, err = CrossValidate(context.TODO(), factory, folds, []string{"unknown"})
+	assert.ErrorIs(t, err, errors.UnknownNameError)
+
+	var failing = func() (Model[float64], error) { return nil, errors.InvalidParameterError }
+	_, err = CrossValidate(context.TODO(), failing, folds, []string{"mae"}, WithConcurrency(2))
+	assert.ErrorIs(t, err, errors.InvalidParameterError)
+}
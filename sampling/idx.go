@@ -0,0 +1,163 @@
+package sampling
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/publiczny81/ml/errors"
+	"io"
+	"math"
+	"os"
+)
+
+// idxFile is a file in IDX format used by MNIST. It starts with magic number holding type of values and number
+// of dimensions, followed by big-endian sizes of dimensions and values
+type idxFile struct {
+	file   *os.File
+	kind   byte
+	count  int
+	size   int
+	offset int64
+}
+
+// sizes of values of IDX types
+var idxSizes = map[byte]int{
+	0x08: 1, // unsigned byte
+	0x09: 1, // signed byte
+	0x0B: 2, // short
+	0x0C: 4, // int
+	0x0D: 4, // float
+	0x0E: 8, // double
+}
+
+func openIDX(path string) (f *idxFile, err error) {
+	var file *os.File
+	if file, err = os.Open(path); err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+		}
+	}()
+	var magic [4]byte
+	if _, err = io.ReadFull(file, magic[:]); err != nil {
+		return nil, errors.WithMessagef(err, "%s: magic number", path)
+	}
+	var width, found = idxSizes[magic[2]]
+	if magic[0] != 0 || magic[1] != 0 || !found || magic[3] == 0 {
+		return nil, errors.WithMessagef(errors.InvalidParameterValueError, "%s: magic number %x", path, magic)
+	}
+	var dimensions = make([]uint32, magic[3])
+	if err = binary.Read(file, binary.BigEndian, dimensions); err != nil {
+		return nil, errors.WithMessagef(err, "%s: dimensions", path)
+	}
+	f = &idxFile{
+		file:   file,
+		kind:   magic[2],
+		count:  int(dimensions[0]),
+		size:   1,
+		offset: int64(4 + 4*len(dimensions)),
+	}
+	for _, d := range dimensions[1:] {
+		f.size *= int(d)
+	}
+	var info os.FileInfo
+	if info, err = file.Stat(); err != nil {
+		return nil, err
+	}
+	if expected := f.offset + int64(f.count*f.size*width); info.Size() < expected {
+		return nil, errors.WithMessagef(errors.InvalidParameterValueError, "%s: size=%d, expected=%d", path, info.Size(), expected)
+	}
+	return
+}
+
+// read reads values of the item at given index
+func (f *idxFile) read(idx int) (values []float64, err error) {
+	var (
+		width = idxSizes[f.kind]
+		data  = make([]byte, f.size*width)
+	)
+	if _, err = f.file.ReadAt(data, f.offset+int64(idx*len(data))); err != nil {
+		return
+	}
+	values = make([]float64, f.size)
+	for i := range values {
+		var b = data[i*width : (i+1)*width]
+		switch f.kind {
+		case 0x08:
+			values[i] = float64(b[0])
+		case 0x09:
+			values[i] = float64(int8(b[0]))
+		case 0x0B:
+			values[i] = float64(int16(binary.BigEndian.Uint16(b)))
+		case 0x0C:
+			values[i] = float64(int32(binary.BigEndian.Uint32(b)))
+		case 0x0D:
+			values[i] = float64(math.Float32frombits(binary.BigEndian.Uint32(b)))
+		case 0x0E:
+			values[i] = math.Float64frombits(binary.BigEndian.Uint64(b))
+		}
+	}
+	return
+}
+
+// IDXSource is a Source of items of IDX file, e.g. MNIST images. Each element is a pair of vectors where the first
+// one holds values of the item flattened in row-major order and the second one values of its label from the companion
+// file, if any. Items are read at offsets computed from the header, so selection does not scan the file
+type IDXSource struct {
+	data   *idxFile
+	labels *idxFile
+}
+
+// NewIDXSource opens IDX file of items and, when labels is not empty, IDX file of their labels.
+// The files are closed with Close
+func NewIDXSource(data, labels string) (s *IDXSource, err error) {
+	s = new(IDXSource)
+	if s.data, err = openIDX(data); err != nil {
+		return nil, err
+	}
+	if labels == "" {
+		return
+	}
+	if s.labels, err = openIDX(labels); err != nil {
+		_ = s.data.file.Close()
+		return nil, err
+	}
+	if s.data.count != s.labels.count {
+		_ = s.Close()
+		return nil, errors.WithMessagef(errors.UnmatchedSizeOfVectorsError, "NewIDXSource: count=%d, labels=%d", s.data.count, s.labels.count)
+	}
+	return
+}
+
+func (s *IDXSource) Count(context.Context) (int, error) {
+	return s.data.count, nil
+}
+
+func (s *IDXSource) Select(_ context.Context, idx int) (e [][]float64, err error) {
+	if idx < 0 || idx >= s.data.count {
+		return
+	}
+	e = make([][]float64, 2)
+	if e[0], err = s.data.read(idx); err != nil {
+		return nil, errors.WithMessagef(err, "item=%d", idx)
+	}
+	if s.labels == nil {
+		return
+	}
+	if e[1], err = s.labels.read(idx); err != nil {
+		return nil, errors.WithMessagef(err, "label=%d", idx)
+	}
+	return
+}
+
+// Close closes the files
+func (s *IDXSource) Close() (err error) {
+	err = s.data.file.Close()
+	if s.labels != nil {
+		if e := s.labels.file.Close(); err == nil {
+			err = e
+		}
+	}
+	return
+}
@@ -0,0 +1,70 @@
+package sampling
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"github.com/publiczny81/ml/errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func idx(kind byte, dimensions []uint32, values any) string {
+	var data = bytes.NewBuffer([]byte{0, 0, kind, byte(len(dimensions))})
+	_ = binary.Write(data, binary.BigEndian, dimensions)
+	_ = binary.Write(data, binary.BigEndian, values)
+	return data.String()
+}
+
+func TestIDXSource(t *testing.T) {
+	var (
+		images = writeFile(t, idx(0x08, []uint32{3, 2, 2}, []uint8{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 255}))
+		labels = writeFile(t, idx(0x08, []uint32{3}, []uint8{7, 2, 1}))
+	)
+	var source, err = NewIDXSource(images, labels)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, source.Close())
+	}()
+	assert.Equal(t, [][][]float64{
+		{{0, 1, 2, 3}, {7}},
+		{{4, 5, 6, 7}, {2}},
+		{{8, 9, 10, 255}, {1}},
+	}, values(t, []Source[[][]float64]{source})[0])
+}
+
+func TestIDXSourceTypes(t *testing.T) {
+	var tests = []struct {
+		kind     byte
+		values   any
+		expected []float64
+	}{
+		{0x09, []int8{-1, 2}, []float64{-1, 2}},
+		{0x0B, []int16{-300, 2}, []float64{-300, 2}},
+		{0x0C, []int32{-70000, 2}, []float64{-70000, 2}},
+		{0x0D, []float32{0.5, -2}, []float64{0.5, -2}},
+		{0x0E, []float64{math.Pi, -2}, []float64{math.Pi, -2}},
+	}
+	for _, test := range tests {
+		var source, err = NewIDXSource(writeFile(t, idx(test.kind, []uint32{1, 2}, test.values)), "")
+		assert.NoError(t, err)
+		var e [][]float64
+		e, err = source.Select(context.TODO(), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, e[0])
+		assert.Nil(t, e[1])
+		assert.NoError(t, source.Close())
+	}
+}
+
+func TestIDXSourceErrors(t *testing.T) {
+	var _, err = NewIDXSource(writeFile(t, idx(0x07, []uint32{1}, []uint8{1})), "")
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+
+	_, err = NewIDXSource(writeFile(t, idx(0x08, []uint32{2, 2}, []uint8{1, 2, 3})), "")
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+
+	_, err = NewIDXSource(writeFile(t, idx(0x08, []uint32{1}, []uint8{1})), writeFile(t, idx(0x08, []uint32{2}, []uint8{1, 2})))
+	assert.ErrorIs(t, err, errors.UnmatchedSizeOfVectorsError)
+}
@@ -0,0 +1,155 @@
+package sampling
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/publiczny81/ml/errors"
+	"io"
+	"math"
+	"os"
+	"sync"
+)
+
+// JSONLSource is a Source of records of JSON Lines file. Each element is a pair of vectors where the first one holds
+// values of feature fields and the second one values of target fields. Fields holding arrays of numbers are
+// flattened, missing fields and nulls are NaN. The offsets of records are indexed on the first scan of the file
+type JSONLSource struct {
+	file     *os.File
+	features []string
+	targets  []string
+
+	mu      sync.Mutex
+	indexed bool
+	offsets []int64
+	ends    []int64
+	lines   []int
+}
+
+// NewJSONLSource opens JSON Lines file. The file is closed with Close
+func NewJSONLSource(path string, features, targets []string) (s *JSONLSource, err error) {
+	if len(features) == 0 {
+		err = errors.WithMessage(errors.InvalidParameterError, "NewJSONLSource: features are empty")
+		return
+	}
+	var file *os.File
+	if file, err = os.Open(path); err != nil {
+		return
+	}
+	s = &JSONLSource{
+		file:     file,
+		features: features,
+		targets:  targets,
+	}
+	return
+}
+
+// index scans the file once and records offsets of non-empty lines
+func (s *JSONLSource) index(ctx context.Context) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.indexed {
+		return
+	}
+	var (
+		reader        = bufio.NewReader(io.NewSectionReader(s.file, 0, math.MaxInt64))
+		offsets, ends []int64
+		lines         []int
+		offset        int64
+		line          []byte
+		number        int
+		readErr       error
+	)
+	for readErr == nil {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		number++
+		line, readErr = reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			offsets = append(offsets, offset)
+			ends = append(ends, offset+int64(len(line)))
+			lines = append(lines, number)
+		}
+		offset += int64(len(line))
+	}
+	s.offsets, s.ends, s.lines, s.indexed = offsets, ends, lines, true
+	return nil
+}
+
+func (s *JSONLSource) Count(ctx context.Context) (int, error) {
+	if err := s.index(ctx); err != nil {
+		return 0, err
+	}
+	return len(s.lines), nil
+}
+
+func (s *JSONLSource) Select(ctx context.Context, idx int) (e [][]float64, err error) {
+	if err = s.index(ctx); err != nil {
+		return
+	}
+	if idx < 0 || idx >= len(s.lines) {
+		return
+	}
+	var (
+		data   = make([]byte, s.ends[idx]-s.offsets[idx])
+		record map[string]json.RawMessage
+	)
+	if _, err = s.file.ReadAt(data, s.offsets[idx]); err != nil && err != io.EOF {
+		return
+	}
+	if err = json.Unmarshal(data, &record); err != nil {
+		return nil, errors.WithMessagef(errors.InvalidParameterValueError, "line=%d: %v", s.lines[idx], err)
+	}
+	e = make([][]float64, 2)
+	for i, fields := range [][]string{s.features, s.targets} {
+		e[i] = make([]float64, 0, len(fields))
+		for _, field := range fields {
+			if e[i], err = appendField(e[i], record[field]); err != nil {
+				return nil, errors.WithMessagef(err, "line=%d, field=%s", s.lines[idx], field)
+			}
+		}
+	}
+	return
+}
+
+// appendField appends value of the field which is a number, a boolean or an array of numbers
+func appendField(values []float64, raw json.RawMessage) ([]float64, error) {
+	var value any
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+	}
+	switch v := value.(type) {
+	case nil:
+		return append(values, math.NaN()), nil
+	case float64:
+		return append(values, v), nil
+	case bool:
+		if v {
+			return append(values, 1), nil
+		}
+		return append(values, 0), nil
+	case []any:
+		for _, item := range v {
+			var f, ok = item.(float64)
+			if !ok {
+				return nil, errors.WithMessagef(errors.InvalidParameterValueError, "value=%v", item)
+			}
+			values = append(values, f)
+		}
+		return values, nil
+	default:
+		return nil, errors.WithMessagef(errors.InvalidParameterValueError, "value=%v", v)
+	}
+}
+
+// Close closes the file
+func (s *JSONLSource) Close() error {
+	return s.file.Close()
+}
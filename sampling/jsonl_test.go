@@ -0,0 +1,43 @@
+package sampling
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestJSONLSource(t *testing.T) {
+	var path = writeFile(t, `{"x": 1, "pixels": [2, 3], "label": true}
+
+{"x": null, "pixels": [4], "label": false}
+{"x": "a", "pixels": [], "label": 1}`)
+	var source, err = NewJSONLSource(path, []string{"x", "pixels"}, []string{"label"})
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, source.Close())
+	}()
+
+	var count int
+	count, err = source.Count(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	var e [][]float64
+	e, err = source.Select(context.TODO(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float64{{1, 2, 3}, {1}}, e)
+
+	e, err = source.Select(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(e[0][0]))
+	assert.Equal(t, [][]float64{{4}, {0}}, [][]float64{e[0][1:], e[1]})
+
+	_, err = source.Select(context.TODO(), 2)
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+	assert.ErrorContains(t, err, "line=4, field=x")
+
+	_, err = NewJSONLSource(path, nil, nil)
+	assert.ErrorIs(t, err, errors.InvalidParameterError)
+}
@@ -10,9 +10,21 @@ type Source[E any] interface {
 	Select(context.Context, int) (E, error)
 }
 
+// Weighted defines contract for Source which provides weights of its samples. Weight reports false when
+// the sample is not weighted, e.g. when a view wraps a source which is not Weighted
+type Weighted interface {
+	Weight(context.Context, int) (weight float64, weighted bool, err error)
+}
+
 type Sample[E any] struct {
 	Value E
 	Error error
+	// Weight scales the contribution of the sample to training when the sample is Weighted
+	Weight float64
+	// Weighted tells whether Weight is given, so an explicit zero weight is distinguished from a missing one
+	Weighted bool
+	// Weights are effective weights of elements of the batch yielded by BatchStrategy when any of them is weighted
+	Weights []float64
 }
 
 func ValueOf[E any](e E) Sample[E] {
@@ -21,6 +33,23 @@ func ValueOf[E any](e E) Sample[E] {
 	}
 }
 
+// WeightedValueOf creates sample with given weight
+func WeightedValueOf[E any](e E, weight float64) Sample[E] {
+	return Sample[E]{
+		Value:    e,
+		Weight:   weight,
+		Weighted: true,
+	}
+}
+
+// EffectiveWeight returns weight of the sample or 1 when the sample is not weighted
+func (s Sample[E]) EffectiveWeight() float64 {
+	if !s.Weighted {
+		return 1
+	}
+	return s.Weight
+}
+
 func Error[E any](e error) Sample[E] {
 	return Sample[E]{
 		Error: e,
@@ -48,6 +77,14 @@ func New[E any](source Source[E], strategy Strategy[E]) (s *Sampler[E]) {
 	return
 }
 
+// SetRand replaces the generator of the strategy if it draws random numbers. Trainers use it to give
+// the sampler its own stream of their generator
+func (s *Sampler[E]) SetRand(rand Rand) {
+	if r, ok := s.strategy.(randomized); ok {
+		r.SetRand(rand)
+	}
+}
+
 func (s *Sampler[E]) Samples(ctx context.Context) <-chan Sample[E] {
 	return s.strategy.Samples(ctx, s.source)
 }
@@ -2,6 +2,7 @@ package sampling
 
 import (
 	"context"
+	"github.com/publiczny81/ml/utils"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"testing"
@@ -35,6 +36,28 @@ func (s *SamplerSuite) TestSamples() {
 	close(ch)
 }
 
+func (s *SamplerSuite) TestSetRand() {
+	var (
+		ctx     = context.TODO()
+		source  = NewSliceSource([]int{0, 1, 2, 3, 4, 5, 6, 7})
+		sampler = New[int](source, NewShuffleStrategy[int](nil))
+		samples = func() (values []int) {
+			for sample := range sampler.Samples(ctx) {
+				values = append(values, sample.Value)
+			}
+			return
+		}
+	)
+	sampler.SetRand(utils.NewPCG(1))
+	var expected = samples()
+	sampler.SetRand(utils.NewPCG(1))
+	s.Equal(expected, samples())
+	s.ElementsMatch([]int{0, 1, 2, 3, 4, 5, 6, 7}, expected)
+
+	// strategies which do not draw random numbers are kept
+	New[int](source, new(SystematicalStrategy[int])).SetRand(utils.NewPCG(1))
+}
+
 type sourceMock struct {
 	mock.Mock
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/publiczny81/ml/calculus/vector"
 	"github.com/publiczny81/ml/metrics"
+	"math"
 )
 
 // SplitSet splits source into 3 sets with given ratio.
@@ -12,32 +13,184 @@ import (
 
 func SplitSet[E any](source Source[E], ratio ...float64) (s []Source[E], err error) {
 	var (
-		count      int
-		start, end int
+		count int
+		start int
 	)
 	if count, err = source.Count(context.Background()); err != nil {
 		return
 	}
-	for i, v := range normalizeRatio(ratio...) {
-		if i == 2 {
+	for _, end := range boundaries(count, normalizeRatio(ratio...)) {
+		s = append(s, MustNewLimitedSource(source, start, end))
+		start = end
+	}
+
+	return
+}
+
+// SplitSetShuffled splits source into 3 sets like SplitSet, but elements are assigned to sets in random order.
+// Sets returned by SplitSet and its variants are views of the source which do not copy elements
+func SplitSetShuffled[E any](source Source[E], rand Rand, ratio ...float64) (s []Source[E], err error) {
+	var count int
+	if count, err = source.Count(context.Background()); err != nil {
+		return
+	}
+	var indices = permutation(rand, count)
+	return split(source, indices, boundaries(count, normalizeRatio(ratio...))), nil
+}
+
+// SplitSetStratified splits source into 3 sets like SplitSet keeping proportions of labels in each set.
+// Remainders of rounding are spread across labels, so sets have the same sizes as sets of SplitSet.
+// Elements of each set are shuffled
+func SplitSetStratified[E any, L comparable](source Source[E], rand Rand, label func(E) L, ratio ...float64) (s []Source[E], err error) {
+	var groups [][]int
+	if groups, err = group(source, label); err != nil {
+		return
+	}
+	var count int
+	for _, g := range groups {
+		count += len(g)
+	}
+	var (
+		sizes    []float64
+		start    int
+		position int
+	)
+	for _, end := range boundaries(count, normalizeRatio(ratio...)) {
+		sizes = append(sizes, float64(end-start))
+		start = end
+	}
+	var indices = make([][]int, len(sizes))
+	// elements of labels are dealt one after another to the set which lags most behind its share
+	// of elements dealt so far
+	for _, g := range groups {
+		shuffle(rand, g)
+		for _, idx := range g {
+			position++
+			var set, lag = 0, math.Inf(-1)
+			for i, size := range sizes {
+				if l := size*float64(position)/float64(count) - float64(len(indices[i])); l > lag {
+					set, lag = i, l
+				}
+			}
+			indices[set] = append(indices[set], idx)
+		}
+	}
+	for _, idx := range indices {
+		shuffle(rand, idx)
+		s = append(s, NewIndexedSource(source, idx))
+	}
+	return
+}
+
+// SplitSetGrouped splits source into 3 sets like SplitSet keeping elements with the same key in the same set.
+// Groups are assigned to sets in random order, so sizes of sets approximate the ratio
+func SplitSetGrouped[E any, K comparable](source Source[E], rand Rand, key func(E) K, ratio ...float64) (s []Source[E], err error) {
+	var groups [][]int
+	if groups, err = group(source, key); err != nil {
+		return
+	}
+	shuffle(rand, groups)
+
+	var (
+		count   int
+		indices []int
+		cuts    []int
+	)
+	for _, g := range groups {
+		count += len(g)
+	}
+	var (
+		bounds = boundaries(count, normalizeRatio(ratio...))
+		set    int
+	)
+	for _, g := range groups {
+		// the group goes to the next set when the current one is full
+		for set < len(bounds)-1 && len(indices) >= bounds[set] {
+			cuts = append(cuts, len(indices))
+			set++
+		}
+		indices = append(indices, g...)
+	}
+	for len(cuts) < len(bounds) {
+		cuts = append(cuts, len(indices))
+	}
+	return split(source, indices, cuts), nil
+}
+
+// boundaries returns end of each set of elements divided with given ratio
+func boundaries(count int, ratio []float64) (ends []int) {
+	var end int
+	for i, v := range ratio {
+		if i == len(ratio)-1 {
 			end = count
 		} else {
 			end += int(float64(count) * v)
 		}
-		s = append(s, MustNewLimitedSource(source, start, end))
+		ends = append(ends, end)
+	}
+	return
+}
+
+// split cuts indices at given ends into views of the source
+func split[E any](source Source[E], indices []int, ends []int) (s []Source[E]) {
+	var start int
+	for _, end := range ends {
+		s = append(s, NewIndexedSource(source, indices[start:end:end]))
 		start = end
 	}
+	return
+}
 
+// group returns indices of elements of the source grouped by the key in order of the first appearance
+func group[E any, K comparable](source Source[E], key func(E) K) (groups [][]int, err error) {
+	var (
+		ctx   = context.Background()
+		count int
+		e     E
+		index = make(map[K]int)
+	)
+	if count, err = source.Count(ctx); err != nil {
+		return
+	}
+	for i := range count {
+		if e, err = source.Select(ctx, i); err != nil {
+			return
+		}
+		var k = key(e)
+		if g, found := index[k]; found {
+			groups[g] = append(groups[g], i)
+			continue
+		}
+		index[k] = len(groups)
+		groups = append(groups, []int{i})
+	}
 	return
 }
 
+func permutation(rand Rand, n int) (p []int) {
+	p = make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	shuffle(rand, p)
+	return
+}
+
+// shuffle permutes elements with Fisher-Yates algorithm
+func shuffle[T any](rand Rand, s []T) {
+	for i := len(s) - 1; i > 0; i-- {
+		var j = rand.IntN(i + 1)
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
 func normalizeRatio(ratio ...float64) (r []float64) {
 	switch len(ratio) {
 	case 0:
 		r = []float64{0.7, 0.15, 0.15}
 		return
 	case 1:
-		r = []float64{ratio[0], 1 - ratio[0]/2, 1 - ratio[0]/2}
+		r = []float64{ratio[0], (1 - ratio[0]) / 2, (1 - ratio[0]) / 2}
 	case 2:
 		r = []float64{ratio[0], ratio[1], 1 - ratio[0] - ratio[1]}
 	default:
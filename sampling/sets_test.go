@@ -2,6 +2,9 @@ package sampling
 
 import (
 	"context"
+	"github.com/publiczny81/ml/utils"
+	"github.com/stretchr/testify/assert"
+	"slices"
 	"testing"
 )
 
@@ -31,3 +34,128 @@ func TestSplitSet(t *testing.T) {
 		t.Fatalf("SplitSet failed: expected 2 elements in the third set, got %d", counts[2])
 	}
 }
+
+func TestNormalizeRatio(t *testing.T) {
+	assert.InDeltaSlice(t, []float64{0.6, 0.2, 0.2}, normalizeRatio(0.6), 1e-12)
+	assert.InDeltaSlice(t, []float64{0.6, 0.3, 0.1}, normalizeRatio(0.6, 0.3), 1e-12)
+	assert.InDeltaSlice(t, []float64{0.7, 0.15, 0.15}, normalizeRatio(), 1e-12)
+}
+
+func TestSplitSetShuffled(t *testing.T) {
+	var (
+		source = NewSliceSource([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+		split  = func(seed uint64) [][]int {
+			var sets, err = SplitSetShuffled[int](source, utils.NewPCG(seed), 0.6)
+			assert.NoError(t, err)
+			return values(t, sets)
+		}
+		actual = split(1)
+	)
+	assert.Equal(t, actual, split(1))
+	assert.Len(t, actual[0], 6)
+	assert.Len(t, actual[1], 2)
+	assert.Len(t, actual[2], 2)
+	assert.ElementsMatch(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, slices.Concat(actual...))
+	assert.NotEqual(t, []int{1, 2, 3, 4, 5, 6}, actual[0])
+}
+
+func TestSplitSetStratified(t *testing.T) {
+	var (
+		elements  = []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+		source    = NewSliceSource(elements)
+		sets, err = SplitSetStratified[int](source, utils.NewPCG(1), func(e int) int { return e }, 0.6, 0.2, 0.2)
+	)
+	assert.NoError(t, err)
+	for i, set := range values(t, sets) {
+		var ones int
+		for _, e := range set {
+			ones += e
+		}
+		assert.Equal(t, len(set)/2, ones, "sets[%d]", i)
+	}
+}
+
+func TestSplitSetStratifiedWithSmallClasses(t *testing.T) {
+	var tests = []struct {
+		Name     string
+		Classes  []int
+		Expected []int
+	}{
+		{
+			Name:     "When there are many classes of 3 elements then validation and test sets are not empty",
+			Classes:  []int{3, 3, 3, 3, 3, 3, 3, 3, 3, 3},
+			Expected: []int{21, 4, 5},
+		},
+		{
+			Name:     "When classes have odd sizes then sets have sizes of SplitSet",
+			Classes:  []int{1, 3, 5, 7, 9, 11},
+			Expected: []int{25, 5, 6},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var elements []int
+			for class, n := range test.Classes {
+				for range n {
+					elements = append(elements, class)
+				}
+			}
+			var (
+				source    = NewSliceSource(elements)
+				sets, err = SplitSetStratified[int](source, utils.NewPCG(1), func(e int) int { return e })
+				sizes     []int
+			)
+			assert.NoError(t, err)
+			for _, set := range values(t, sets) {
+				sizes = append(sizes, len(set))
+			}
+			assert.Equal(t, test.Expected, sizes)
+		})
+	}
+}
+
+func TestSplitSetGrouped(t *testing.T) {
+	type element struct {
+		Group int
+		Value int
+	}
+	var (
+		elements []element
+		groups   = make(map[int]int)
+	)
+	for i := range 20 {
+		elements = append(elements, element{Group: i % 5, Value: i})
+	}
+	var sets, err = SplitSetGrouped[element](NewSliceSource(elements), utils.NewPCG(1), func(e element) int { return e.Group }, 0.6, 0.2, 0.2)
+	assert.NoError(t, err)
+
+	var total int
+	for i, set := range values(t, sets) {
+		assert.Len(t, set, 4*[]int{3, 1, 1}[i])
+		for _, e := range set {
+			if g, found := groups[e.Group]; found {
+				assert.Equal(t, i, g, "group %d is split", e.Group)
+			}
+			groups[e.Group] = i
+		}
+		total += len(set)
+	}
+	assert.Equal(t, 20, total)
+}
+
+func values[E any](t *testing.T, sets []Source[E]) (result [][]E) {
+	for _, set := range sets {
+		var (
+			count, err = set.Count(context.TODO())
+			values     []E
+		)
+		assert.NoError(t, err)
+		for i := range count {
+			var e, err = set.Select(context.TODO(), i)
+			assert.NoError(t, err)
+			values = append(values, e)
+		}
+		result = append(result, values)
+	}
+	return
+}
@@ -80,3 +80,92 @@ func (s *LimitedSource[E]) Count(context.Context) (int, error) {
 func (s *LimitedSource[E]) Select(ctx context.Context, idx int) (E, error) {
 	return s.source.Select(ctx, s.from+idx)
 }
+
+func (s *LimitedSource[E]) selectWeighted(ctx context.Context, idx int) (Sample[E], error) {
+	return selectSample(ctx, s.source, s.from+idx)
+}
+
+// Weight returns weight of the sample of underlying source if it is Weighted
+func (s *LimitedSource[E]) Weight(ctx context.Context, idx int) (float64, bool, error) {
+	if w, ok := s.source.(Weighted); ok {
+		return w.Weight(ctx, s.from+idx)
+	}
+	return 0, false, nil
+}
+
+// WeightedSource assigns weights to the samples of the source
+type WeightedSource[E any] struct {
+	Source[E]
+	weights []float64
+}
+
+func NewWeightedSource[E any](source Source[E], weights []float64) (s *WeightedSource[E], err error) {
+	if source == nil || reflect.ValueOf(source).IsZero() {
+		err = errors.WithMessage(errors.InvalidParameterError, "NewWeightedSource: source is nil")
+		return
+	}
+	for i, w := range weights {
+		if w < 0 {
+			err = errors.WithMessagef(errors.InvalidParameterValueError, "NewWeightedSource: weights[%d] is negative", i)
+			return
+		}
+	}
+	s = &WeightedSource[E]{
+		Source:  source,
+		weights: weights,
+	}
+	return
+}
+
+func (s *WeightedSource[E]) Weight(ctx context.Context, idx int) (float64, bool, error) {
+	var count, err = s.Count(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	if count != len(s.weights) {
+		return 0, false, errors.WithMessagef(errors.UnmatchedSizeOfVectorsError, "WeightedSource: count=%d, len(weights)=%d", count, len(s.weights))
+	}
+	if idx < 0 || idx >= len(s.weights) {
+		return 0, false, nil
+	}
+	return s.weights[idx], true, nil
+}
+
+// IndexedSource is a view of the source which selects elements through indices
+type IndexedSource[E any] struct {
+	source  Source[E]
+	indices []int
+}
+
+func NewIndexedSource[E any](source Source[E], indices []int) *IndexedSource[E] {
+	return &IndexedSource[E]{
+		source:  source,
+		indices: indices,
+	}
+}
+
+func (s *IndexedSource[E]) Count(context.Context) (int, error) {
+	return len(s.indices), nil
+}
+
+func (s *IndexedSource[E]) Select(ctx context.Context, idx int) (e E, err error) {
+	if idx < 0 || idx >= len(s.indices) {
+		return
+	}
+	return s.source.Select(ctx, s.indices[idx])
+}
+
+func (s *IndexedSource[E]) selectWeighted(ctx context.Context, idx int) (sample Sample[E], err error) {
+	if idx < 0 || idx >= len(s.indices) {
+		return
+	}
+	return selectSample(ctx, s.source, s.indices[idx])
+}
+
+// Weight returns weight of the sample of underlying source if it is Weighted
+func (s *IndexedSource[E]) Weight(ctx context.Context, idx int) (float64, bool, error) {
+	if w, ok := s.source.(Weighted); ok && idx >= 0 && idx < len(s.indices) {
+		return w.Weight(ctx, s.indices[idx])
+	}
+	return 0, false, nil
+}
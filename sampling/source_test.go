@@ -3,6 +3,7 @@ package sampling
 import (
 	"context"
 	"github.com/publiczny81/ml/errors"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"testing"
 )
@@ -126,3 +127,99 @@ func (s *LimitedSourceSuite) TestNewLimitedSource() {
 		})
 	}
 }
+
+type WeightedSourceSuite struct {
+	suite.Suite
+}
+
+func TestWeightedSource(t *testing.T) {
+	suite.Run(t, new(WeightedSourceSuite))
+}
+
+func (s *WeightedSourceSuite) TestNewWeightedSource() {
+	var tests = []struct {
+		Name    string
+		Source  *SliceSource[[]float64, float64]
+		Weights []float64
+		Error   error
+	}{
+		{
+			Name:  "When source is nil then return error",
+			Error: errors.InvalidParameterError,
+		},
+		{
+			Name:    "When weight is negative then return error",
+			Source:  NewSliceSource([]float64{1, 2}),
+			Weights: []float64{1, -1},
+			Error:   errors.InvalidParameterValueError,
+		},
+		{
+			Name:    "When weights are valid then return source",
+			Source:  NewSliceSource([]float64{1, 2}),
+			Weights: []float64{1, 2},
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.Name, func() {
+			actual, err := NewWeightedSource[float64](test.Source, test.Weights)
+			if test.Error != nil {
+				s.ErrorIs(err, test.Error)
+				return
+			}
+			s.NoError(err)
+			s.NotNil(actual)
+		})
+	}
+}
+
+func (s *WeightedSourceSuite) TestSamples() {
+	var (
+		ctx       = context.TODO()
+		source, _ = NewWeightedSource[float64](NewSliceSource([]float64{1, 2, 3}), []float64{0.5, 2, 1})
+		limited   = MustNewLimitedSource[float64](source, 1, 3)
+		actual    []Sample[float64]
+	)
+	for sample := range new(SystematicalStrategy[float64]).Samples(ctx, limited) {
+		actual = append(actual, sample)
+	}
+	s.Equal([]Sample[float64]{WeightedValueOf(2.0, 2), WeightedValueOf(3.0, 1)}, actual)
+}
+
+func (s *WeightedSourceSuite) TestWeightWithUnmatchedSizes() {
+	var source, _ = NewWeightedSource[float64](NewSliceSource([]float64{1, 2, 3}), []float64{1})
+	_, _, err := source.Weight(context.TODO(), 0)
+	s.ErrorIs(err, errors.UnmatchedSizeOfVectorsError)
+}
+
+func TestEffectiveWeight(t *testing.T) {
+	assert.Equal(t, 1.0, ValueOf(1.0).EffectiveWeight())
+	assert.Equal(t, 0.5, WeightedValueOf(1.0, 0.5).EffectiveWeight())
+	assert.Equal(t, 0.0, WeightedValueOf(1.0, 0).EffectiveWeight())
+}
+
+func TestWeightedSourceWithZeroWeight(t *testing.T) {
+	var source, _ = NewWeightedSource[float64](NewSliceSource([]float64{1, 2}), []float64{0, 1})
+	var actual []Sample[float64]
+	for sample := range new(SystematicalStrategy[float64]).Samples(context.TODO(), source) {
+		actual = append(actual, sample)
+	}
+	assert.Equal(t, []Sample[float64]{WeightedValueOf(1.0, 0), WeightedValueOf(2.0, 1)}, actual)
+	assert.Equal(t, 0.0, actual[0].EffectiveWeight())
+}
+
+func TestIndexedSource(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		source, _ = NewWeightedSource[float64](NewSliceSource([]float64{1, 2, 3}), []float64{0.1, 0.2, 0.3})
+		indexed   = NewIndexedSource[float64](source, []int{2, 0})
+		actual    []Sample[float64]
+	)
+	for sample := range new(SystematicalStrategy[float64]).Samples(ctx, indexed) {
+		actual = append(actual, sample)
+	}
+	assert.Equal(t, []Sample[float64]{WeightedValueOf(3.0, 0.3), WeightedValueOf(1.0, 0.1)}, actual)
+
+	e, err := indexed.Select(ctx, 5)
+	assert.NoError(t, err)
+	assert.Zero(t, e)
+}
@@ -14,14 +14,14 @@ func (s *SystematicalStrategy[E]) Samples(ctx context.Context, source Source[E])
 		var (
 			current    = 0
 			limit, err = source.Count(ctx)
-			e          E
+			sample     Sample[E]
 		)
 		if err != nil {
 			ch <- Error[E](err)
 			return
 		}
 		for current < limit {
-			if e, err = source.Select(ctx, current); err != nil {
+			if sample, err = selectSample(ctx, source, current); err != nil {
 				ch <- Error[E](err)
 				return
 			}
@@ -30,7 +30,7 @@ func (s *SystematicalStrategy[E]) Samples(ctx context.Context, source Source[E])
 			case <-ctx.Done():
 				ch <- Error[E](ctx.Err())
 				return
-			case ch <- ValueOf(e):
+			case ch <- sample:
 			}
 		}
 	}()
@@ -43,6 +43,11 @@ type Rand interface {
 	IntN(n int) int
 }
 
+// randomized is implemented by strategies which draw random numbers
+type randomized interface {
+	SetRand(Rand)
+}
+
 type RandomStrategy[E any] struct {
 	rand Rand
 }
@@ -53,6 +58,11 @@ func NewRandomStrategy[E any](rand Rand) *RandomStrategy[E] {
 	}
 }
 
+// SetRand replaces the generator of the strategy
+func (s *RandomStrategy[E]) SetRand(rand Rand) {
+	s.rand = rand
+}
+
 func (s *RandomStrategy[E]) Samples(ctx context.Context, source Source[E]) <-chan Sample[E] {
 	var (
 		ch = make(chan Sample[E])
@@ -62,13 +72,13 @@ func (s *RandomStrategy[E]) Samples(ctx context.Context, source Source[E]) <-cha
 
 		var (
 			limit, err = source.Count(ctx)
-			e          E
+			sample     Sample[E]
 		)
 		if err != nil {
 			ch <- Error[E](err)
 			return
 		}
-		if e, err = source.Select(ctx, s.rand.IntN(limit)); err != nil {
+		if sample, err = selectSample(ctx, source, s.rand.IntN(limit)); err != nil {
 			ch <- Error[E](err)
 			return
 		}
@@ -77,9 +87,335 @@ func (s *RandomStrategy[E]) Samples(ctx context.Context, source Source[E]) <-cha
 		case <-ctx.Done():
 			ch <- Error[E](ctx.Err())
 			return
-		case ch <- ValueOf(e):
+		case ch <- sample:
 		}
 
 	}()
 	return ch
 }
+
+// weightedSelector is implemented by views which select elements of their sources together with weights,
+// so elements are selected once and weights of elements reach samples through views
+type weightedSelector[E any] interface {
+	selectWeighted(ctx context.Context, idx int) (Sample[E], error)
+}
+
+// selectSample selects element of the source and its weight. The weight is given by the source if it is Weighted,
+// otherwise by the element if it is weighted, e.g. Example
+func selectSample[E any](ctx context.Context, source Source[E], idx int) (sample Sample[E], err error) {
+	if s, ok := source.(weightedSelector[E]); ok {
+		sample, err = s.selectWeighted(ctx, idx)
+	} else if sample.Value, err = source.Select(ctx, idx); err == nil {
+		if w, ok := source.(Weighted); ok {
+			sample.Weight, sample.Weighted, err = w.Weight(ctx, idx)
+		}
+	}
+	if err != nil || sample.Weighted {
+		return
+	}
+	if w, ok := any(sample.Value).(interface{ weight() (float64, bool) }); ok {
+		sample.Weight, sample.Weighted = w.weight()
+	}
+	return
+}
+
+// ShuffleStrategy yields every element of the source once per call of Samples in random order
+type ShuffleStrategy[E any] struct {
+	rand Rand
+}
+
+func NewShuffleStrategy[E any](rand Rand) *ShuffleStrategy[E] {
+	return &ShuffleStrategy[E]{
+		rand: rand,
+	}
+}
+
+// SetRand replaces the generator of the strategy
+func (s *ShuffleStrategy[E]) SetRand(rand Rand) {
+	s.rand = rand
+}
+
+func (s *ShuffleStrategy[E]) Samples(ctx context.Context, source Source[E]) <-chan Sample[E] {
+	var (
+		ch = make(chan Sample[E])
+	)
+	go func() {
+		defer close(ch)
+
+		var limit, err = source.Count(ctx)
+		if err != nil {
+			ch <- Error[E](err)
+			return
+		}
+		emit(ctx, ch, source, permutation(s.rand, limit))
+	}()
+	return ch
+}
+
+// BootstrapStrategy yields given number of elements of the source drawn with replacement
+type BootstrapStrategy[E any] struct {
+	rand  Rand
+	count int
+}
+
+// NewBootstrapStrategy creates strategy drawing count elements. When count is not positive it draws as many
+// elements as the source has
+func NewBootstrapStrategy[E any](rand Rand, count int) *BootstrapStrategy[E] {
+	return &BootstrapStrategy[E]{
+		rand:  rand,
+		count: count,
+	}
+}
+
+// SetRand replaces the generator of the strategy
+func (s *BootstrapStrategy[E]) SetRand(rand Rand) {
+	s.rand = rand
+}
+
+func (s *BootstrapStrategy[E]) Samples(ctx context.Context, source Source[E]) <-chan Sample[E] {
+	var (
+		ch = make(chan Sample[E])
+	)
+	go func() {
+		defer close(ch)
+
+		var limit, err = source.Count(ctx)
+		if err != nil {
+			ch <- Error[E](err)
+			return
+		}
+		if limit == 0 {
+			return
+		}
+		var n = s.count
+		if n <= 0 {
+			n = limit
+		}
+		var indices = make([]int, n)
+		for i := range indices {
+			indices[i] = s.rand.IntN(limit)
+		}
+		emit(ctx, ch, source, indices)
+	}()
+	return ch
+}
+
+// BatchStrategy groups samples yielded by the strategy into batches of given size. Effective weights of samples
+// are carried to Weights of the batch when any sample of the batch is weighted
+type BatchStrategy[E any] struct {
+	strategy Strategy[E]
+	size     int
+	dropLast bool
+}
+
+// NewBatchStrategy creates strategy yielding batches of given size. When dropLast is set the last batch
+// is dropped if it is smaller than size
+func NewBatchStrategy[E any](strategy Strategy[E], size int, dropLast bool) *BatchStrategy[E] {
+	return &BatchStrategy[E]{
+		strategy: strategy,
+		size:     max(1, size),
+		dropLast: dropLast,
+	}
+}
+
+// SetRand replaces the generator of the wrapped strategy if it draws random numbers
+func (s *BatchStrategy[E]) SetRand(rand Rand) {
+	if r, ok := s.strategy.(randomized); ok {
+		r.SetRand(rand)
+	}
+}
+
+func (s *BatchStrategy[E]) Samples(ctx context.Context, source Source[E]) <-chan Sample[[]E] {
+	var (
+		ch = make(chan Sample[[]E])
+	)
+	go func() {
+		defer close(ch)
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		var (
+			batch    = make([]E, 0, s.size)
+			weights  = make([]float64, 0, s.size)
+			weighted bool
+			samples  = s.strategy.Samples(ctx, source)
+		)
+		defer Drain(cancel, samples)
+		var value = func() (b Sample[[]E]) {
+			if b = ValueOf(batch); weighted {
+				b.Weights = weights
+			}
+			return
+		}
+		for sample := range samples {
+			if sample.Error != nil {
+				ch <- Error[[]E](sample.Error)
+				return
+			}
+			weighted = weighted || sample.Weighted
+			weights = append(weights, sample.EffectiveWeight())
+			if batch = append(batch, sample.Value); len(batch) < s.size {
+				continue
+			}
+			if !send(ctx, ch, value()) {
+				return
+			}
+			batch, weights, weighted = make([]E, 0, s.size), make([]float64, 0, s.size), false
+		}
+		if len(batch) > 0 && !s.dropLast {
+			send(ctx, ch, value())
+		}
+	}()
+	return ch
+}
+
+// emit selects elements of the source at given indices and sends them to the channel
+func emit[E any](ctx context.Context, ch chan<- Sample[E], source Source[E], indices []int) {
+	for _, idx := range indices {
+		var sample, err = selectSample(ctx, source, idx)
+		if err != nil {
+			ch <- Error[E](err)
+			return
+		}
+		if !send(ctx, ch, sample) {
+			return
+		}
+	}
+}
+
+// Drain cancels the context of the producer of the channel and discards elements left in the channel until it
+// is closed. Consumers defer it, so the producer does not block on sending when its consumer stops early
+func Drain[E any](cancel context.CancelFunc, ch <-chan E) {
+	cancel()
+	for range ch {
+	}
+}
+
+// send sends the sample unless the context is done. In that case it sends the error of the context
+func send[E any](ctx context.Context, ch chan<- Sample[E], sample Sample[E]) bool {
+	select {
+	case <-ctx.Done():
+		ch <- Error[E](ctx.Err())
+		return false
+	case ch <- sample:
+		return true
+	}
+}
+
+// PrefetchStrategy selects elements of the source ahead of the consumer, e.g. when selection reads a file.
+// The order of elements is given by the strategy which draws indices of the source
+type PrefetchStrategy[E any] struct {
+	strategy    Strategy[int]
+	ahead       int
+	concurrency int
+}
+
+// NewPrefetchStrategy creates strategy selecting at most ahead elements before they are consumed, with at most
+// concurrency selections running at the same time
+func NewPrefetchStrategy[E any](strategy Strategy[int], ahead, concurrency int) *PrefetchStrategy[E] {
+	return &PrefetchStrategy[E]{
+		strategy:    strategy,
+		ahead:       max(1, ahead),
+		concurrency: max(1, concurrency),
+	}
+}
+
+// SetRand replaces the generator of the strategy of indices if it draws random numbers
+func (s *PrefetchStrategy[E]) SetRand(rand Rand) {
+	if r, ok := s.strategy.(randomized); ok {
+		r.SetRand(rand)
+	}
+}
+
+func (s *PrefetchStrategy[E]) Samples(ctx context.Context, source Source[E]) <-chan Sample[E] {
+	var (
+		ch = make(chan Sample[E])
+	)
+	go func() {
+		defer close(ch)
+
+		var limit, err = source.Count(ctx)
+		if err != nil {
+			ch <- Error[E](err)
+			return
+		}
+		var (
+			prefetch, cancel = context.WithCancel(ctx)
+			futures          = make(chan chan Sample[E], s.ahead)
+		)
+		defer cancel()
+		go s.prefetch(prefetch, source, limit, futures)
+
+		s.consume(ctx, ch, futures)
+		// prefetching is stopped and pending selections are awaited
+		cancel()
+		for future := range futures {
+			<-future
+		}
+	}()
+	return ch
+}
+
+// consume sends results of selections in order until an error occurs. Prefetching stops without an error when
+// the context is done, so the error of the context is sent in that case
+func (s *PrefetchStrategy[E]) consume(ctx context.Context, ch chan<- Sample[E], futures <-chan chan Sample[E]) {
+	for future := range futures {
+		var sample = <-future
+		if sample.Error != nil {
+			ch <- sample
+			return
+		}
+		if !send(ctx, ch, sample) {
+			return
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		ch <- Error[E](err)
+	}
+}
+
+// prefetch draws indices of the source, starts selection of each of them and passes its future result in order
+// of drawing
+func (s *PrefetchStrategy[E]) prefetch(ctx context.Context, source Source[E], limit int, futures chan<- chan Sample[E]) {
+	defer close(futures)
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithCancel(ctx)
+	var indices = s.strategy.Samples(ctx, indexSource(limit))
+	defer Drain(cancel, indices)
+	var semaphore = make(chan struct{}, s.concurrency)
+	for sample := range indices {
+		var future = make(chan Sample[E], 1)
+		if sample.Error != nil {
+			future <- Error[E](sample.Error)
+			futures <- future
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case futures <- future:
+		}
+		semaphore <- struct{}{}
+		go func(idx int) {
+			defer func() {
+				<-semaphore
+			}()
+			var selected, err = selectSample(ctx, source, idx)
+			if err != nil {
+				selected = Error[E](err)
+			}
+			future <- selected
+		}(sample.Value)
+	}
+}
+
+// indexSource is a Source of its own indices. Strategies draw indices from it
+type indexSource int
+
+func (s indexSource) Count(context.Context) (int, error) {
+	return int(s), nil
+}
+
+func (s indexSource) Select(_ context.Context, idx int) (int, error) {
+	return idx, nil
+}
@@ -3,6 +3,7 @@ package sampling
 import (
 	"context"
 	"github.com/pkg/errors"
+	"github.com/publiczny81/ml/utils"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"testing"
@@ -169,6 +170,225 @@ func (s *RandomStrategySuite) TestSamples() {
 	}
 }
 
+type ShuffleStrategySuite struct {
+	suite.Suite
+}
+
+func TestShuffleStrategy(t *testing.T) {
+	suite.Run(t, new(ShuffleStrategySuite))
+}
+
+func (s *ShuffleStrategySuite) TestSamples() {
+	var (
+		rand   = new(randMock)
+		source = NewSliceSource([]float64{1, 2, 3, 4})
+		actual []Sample[float64]
+	)
+	rand.On("IntN", 4).Return(0)
+	rand.On("IntN", 3).Return(2)
+	rand.On("IntN", 2).Return(0)
+	for sample := range NewShuffleStrategy[float64](rand).Samples(context.TODO(), source) {
+		actual = append(actual, sample)
+	}
+	s.Equal([]Sample[float64]{ValueOf(2.0), ValueOf(4.0), ValueOf(3.0), ValueOf(1.0)}, actual)
+}
+
+func (s *ShuffleStrategySuite) TestSamplesAreSeeded() {
+	var (
+		source  = NewSliceSource([]float64{1, 2, 3, 4, 5, 6, 7, 8})
+		shuffle = func(seed uint64) (values []float64) {
+			var strategy = NewShuffleStrategy[float64](utils.NewPCG(seed))
+			for sample := range strategy.Samples(context.TODO(), source) {
+				values = append(values, sample.Value)
+			}
+			return
+		}
+		first = shuffle(1)
+	)
+	s.Equal(first, shuffle(1))
+	s.ElementsMatch([]float64{1, 2, 3, 4, 5, 6, 7, 8}, first)
+}
+
+func (s *ShuffleStrategySuite) TestSamplesWithCountError() {
+	var (
+		err    = errors.New("error")
+		source = new(sourceMock)
+		actual []Sample[float64]
+	)
+	source.On("Count", context.TODO()).Return(0, err)
+	for sample := range NewShuffleStrategy[float64](new(randMock)).Samples(context.TODO(), source) {
+		actual = append(actual, sample)
+	}
+	s.Equal([]Sample[float64]{Error[float64](err)}, actual)
+}
+
+type BootstrapStrategySuite struct {
+	suite.Suite
+}
+
+func TestBootstrapStrategy(t *testing.T) {
+	suite.Run(t, new(BootstrapStrategySuite))
+}
+
+func (s *BootstrapStrategySuite) TestSamples() {
+	var tests = []struct {
+		Name     string
+		Count    int
+		Expected []Sample[float64]
+	}{
+		{
+			Name:     "When count is set then strategy draws count elements with replacement",
+			Count:    4,
+			Expected: []Sample[float64]{ValueOf(2.0), ValueOf(2.0), ValueOf(2.0), ValueOf(2.0)},
+		},
+		{
+			Name:     "When count is not set then strategy draws as many elements as source has",
+			Expected: []Sample[float64]{ValueOf(2.0), ValueOf(2.0), ValueOf(2.0)},
+		},
+		{
+			Name:     "When count is negative then strategy draws as many elements as source has",
+			Count:    -1,
+			Expected: []Sample[float64]{ValueOf(2.0), ValueOf(2.0), ValueOf(2.0)},
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.Name, func() {
+			var (
+				rand   = new(randMock)
+				actual []Sample[float64]
+			)
+			rand.On("IntN", 3).Return(1)
+			for sample := range NewBootstrapStrategy[float64](rand, test.Count).Samples(context.TODO(), NewSliceSource([]float64{1, 2, 3})) {
+				actual = append(actual, sample)
+			}
+			s.Equal(test.Expected, actual)
+		})
+	}
+}
+
+type BatchStrategySuite struct {
+	suite.Suite
+}
+
+func TestBatchStrategy(t *testing.T) {
+	suite.Run(t, new(BatchStrategySuite))
+}
+
+func (s *BatchStrategySuite) TestSamples() {
+	var tests = []struct {
+		Name     string
+		DropLast bool
+		Expected []Sample[[]float64]
+	}{
+		{
+			Name:     "When last batch is kept then it may be smaller",
+			Expected: []Sample[[]float64]{ValueOf([]float64{1, 2}), ValueOf([]float64{3, 4}), ValueOf([]float64{5})},
+		},
+		{
+			Name:     "When last batch is dropped then all batches have the same size",
+			DropLast: true,
+			Expected: []Sample[[]float64]{ValueOf([]float64{1, 2}), ValueOf([]float64{3, 4})},
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.Name, func() {
+			var (
+				strategy = NewBatchStrategy[float64](new(SystematicalStrategy[float64]), 2, test.DropLast)
+				actual   []Sample[[]float64]
+			)
+			for sample := range strategy.Samples(context.TODO(), NewSliceSource([]float64{1, 2, 3, 4, 5})) {
+				actual = append(actual, sample)
+			}
+			s.Equal(test.Expected, actual)
+		})
+	}
+}
+
+func (s *BatchStrategySuite) TestSamplesCarryWeights() {
+	var (
+		source, _ = NewWeightedSource[float64](NewSliceSource([]float64{1, 2, 3, 4, 5}), []float64{0.5, 2, 1, 1, 0})
+		actual    []Sample[[]float64]
+	)
+	for sample := range NewBatchStrategy[float64](new(SystematicalStrategy[float64]), 2, false).Samples(context.TODO(), source) {
+		actual = append(actual, sample)
+	}
+	s.Equal([]Sample[[]float64]{
+		{Value: []float64{1, 2}, Weights: []float64{0.5, 2}},
+		{Value: []float64{3, 4}, Weights: []float64{1, 1}},
+		{Value: []float64{5}, Weights: []float64{0}},
+	}, actual)
+}
+
+func (s *BatchStrategySuite) TestSamplesWithError() {
+	var (
+		err    = errors.New("error")
+		source = new(sourceMock)
+		actual []Sample[[]float64]
+	)
+	// the wrapped strategy gets context which is cancelled when batching stops
+	var ctx = mock.AnythingOfType("*context.cancelCtx")
+	source.On("Count", ctx).Return(3, nil)
+	source.On("Select", ctx, 0).Return(1.0, nil)
+	source.On("Select", ctx, 1).Return(float64(0), err)
+	for sample := range NewBatchStrategy[float64](new(SystematicalStrategy[float64]), 2, false).Samples(context.TODO(), source) {
+		actual = append(actual, sample)
+	}
+	s.Equal([]Sample[[]float64]{Error[[]float64](err)}, actual)
+}
+
+type PrefetchStrategySuite struct {
+	suite.Suite
+}
+
+func TestPrefetchStrategy(t *testing.T) {
+	suite.Run(t, new(PrefetchStrategySuite))
+}
+
+func (s *PrefetchStrategySuite) TestSamples() {
+	var (
+		source, _ = NewWeightedSource[float64](NewSliceSource([]float64{1, 2, 3, 4, 5}), []float64{1, 2, 3, 4, 5})
+		expected  []Sample[float64]
+		actual    []Sample[float64]
+	)
+	for sample := range NewShuffleStrategy[float64](utils.NewPCG(1)).Samples(context.TODO(), source) {
+		expected = append(expected, sample)
+	}
+	for sample := range NewPrefetchStrategy[float64](NewShuffleStrategy[int](utils.NewPCG(1)), 2, 3).Samples(context.TODO(), source) {
+		actual = append(actual, sample)
+	}
+	s.Equal(expected, actual)
+}
+
+func (s *PrefetchStrategySuite) TestSamplesWithError() {
+	var (
+		err    = errors.New("error")
+		source = new(sourceMock)
+		actual []Sample[float64]
+	)
+	source.On("Count", context.TODO()).Return(3, nil)
+	source.On("Select", mock.Anything, 0).Return(1.0, nil)
+	source.On("Select", mock.Anything, 1).Return(float64(0), err)
+	source.On("Select", mock.Anything, 2).Return(3.0, nil).Maybe()
+	for sample := range NewPrefetchStrategy[float64](new(SystematicalStrategy[int]), 3, 3).Samples(context.TODO(), source) {
+		actual = append(actual, sample)
+	}
+	s.Equal([]Sample[float64]{ValueOf(1.0), Error[float64](err)}, actual)
+}
+
+func (s *PrefetchStrategySuite) TestSamplesWhenConsumerStops() {
+	var (
+		ctx, cancel = context.WithCancel(context.TODO())
+		samples     = NewPrefetchStrategy[float64](new(SystematicalStrategy[int]), 2, 2).Samples(ctx, NewSliceSource(make([]float64, 100)))
+	)
+	s.Equal(ValueOf(0.0), <-samples)
+	cancel()
+	var last Sample[float64]
+	for sample := range samples {
+		last = sample
+	}
+	s.ErrorIs(last.Error, context.Canceled)
+}
+
 type randMock struct {
 	mock.Mock
 }
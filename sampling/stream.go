@@ -0,0 +1,223 @@
+package sampling
+
+import (
+	"container/heap"
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"io"
+	"math"
+)
+
+// Stream provides elements one after another without knowing their number in advance, e.g. records replayed
+// from a log or an endless generator. The channel is closed when the stream ends. Sampler is a Stream as well,
+// so streams can be passed to trainers
+type Stream[E any] interface {
+	Samples(ctx context.Context) <-chan Sample[E]
+}
+
+// ChannelStream adapts channel of elements. The channel can be consumed only once
+type ChannelStream[E any] struct {
+	ch <-chan E
+}
+
+func NewChannelStream[E any](ch <-chan E) *ChannelStream[E] {
+	return &ChannelStream[E]{
+		ch: ch,
+	}
+}
+
+func (s *ChannelStream[E]) Samples(ctx context.Context) <-chan Sample[E] {
+	var (
+		ch = make(chan Sample[E])
+	)
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				ch <- Error[E](ctx.Err())
+				return
+			case e, ok := <-s.ch:
+				if !ok || !send(ctx, ch, ValueOf(e)) {
+					return
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// GeneratorStream yields elements returned by the function until it returns io.EOF
+type GeneratorStream[E any] struct {
+	next func(ctx context.Context) (E, error)
+}
+
+func NewGeneratorStream[E any](next func(ctx context.Context) (E, error)) *GeneratorStream[E] {
+	return &GeneratorStream[E]{
+		next: next,
+	}
+}
+
+func (s *GeneratorStream[E]) Samples(ctx context.Context) <-chan Sample[E] {
+	var (
+		ch = make(chan Sample[E])
+	)
+	go func() {
+		defer close(ch)
+		for {
+			var e, err = s.next(ctx)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				ch <- Error[E](err)
+				return
+			}
+			if !send(ctx, ch, ValueOf(e)) {
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// FloatRand defines contract for random number generator drawing real numbers, e.g. utils.NewPCG
+type FloatRand interface {
+	Rand
+	// Float64 generates a number in range <0, 1)
+	Float64() float64
+}
+
+// consume reads the stream until it ends, calling f with each element and its position in the stream
+func consume[E any](ctx context.Context, stream Stream[E], f func(sample Sample[E], i int)) (err error) {
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithCancel(ctx)
+	var samples = stream.Samples(ctx)
+	defer Drain(cancel, samples)
+	var i int
+	for sample := range samples {
+		if sample.Error != nil {
+			return sample.Error
+		}
+		f(sample, i)
+		i++
+	}
+	return ctx.Err()
+}
+
+// ReservoirR keeps uniform sample of at most k elements of the stream using Algorithm R. It draws a random
+// number for each element of the stream
+func ReservoirR[E any](ctx context.Context, stream Stream[E], k int, rand Rand) (reservoir []E, err error) {
+	if k < 1 {
+		return nil, errors.WithMessagef(errors.InvalidParameterValueError, "ReservoirR: k=%d", k)
+	}
+	reservoir = make([]E, 0, k)
+	err = consume(ctx, stream, func(sample Sample[E], i int) {
+		if i < k {
+			reservoir = append(reservoir, sample.Value)
+		} else if j := rand.IntN(i + 1); j < k {
+			reservoir[j] = sample.Value
+		}
+	})
+	return
+}
+
+// ReservoirL keeps uniform sample of at most k elements of the stream using Algorithm L. It draws random numbers
+// only for elements which enter the reservoir, so it is faster than ReservoirR for long streams
+func ReservoirL[E any](ctx context.Context, stream Stream[E], k int, rand FloatRand) (reservoir []E, err error) {
+	if k < 1 {
+		return nil, errors.WithMessagef(errors.InvalidParameterValueError, "ReservoirL: k=%d", k)
+	}
+	var (
+		w    = math.Exp(math.Log(uniform(rand)) / float64(k))
+		next = k
+	)
+	// skip returns position of the next element entering the reservoir
+	var skip = func(i int) int {
+		return i + int(math.Floor(math.Log(uniform(rand))/math.Log(1-w))) + 1
+	}
+	reservoir = make([]E, 0, k)
+	err = consume(ctx, stream, func(sample Sample[E], i int) {
+		switch {
+		case i < k:
+			if reservoir = append(reservoir, sample.Value); i == k-1 {
+				next = skip(i)
+			}
+		case i == next:
+			reservoir[rand.IntN(k)] = sample.Value
+			w *= math.Exp(math.Log(uniform(rand)) / float64(k))
+			next = skip(i)
+		}
+	})
+	return
+}
+
+// WeightedReservoir keeps sample of at most k elements of the stream where the chance of an element to be kept
+// is proportional to its weight, using Algorithm A-ExpJ. Elements which are not weighted have weight 1 and
+// elements of zero weight are never kept
+func WeightedReservoir[E any](ctx context.Context, stream Stream[E], k int, rand FloatRand) (reservoir []E, err error) {
+	if k < 1 {
+		return nil, errors.WithMessagef(errors.InvalidParameterValueError, "WeightedReservoir: k=%d", k)
+	}
+	var (
+		keys = make(keyed[E], 0, k)
+		// jump is the total weight of elements skipped before the next element enters the reservoir
+		jump float64
+	)
+	err = consume(ctx, stream, func(sample Sample[E], i int) {
+		var weight = sample.EffectiveWeight()
+		if weight <= 0 {
+			return
+		}
+		if len(keys) < k {
+			heap.Push(&keys, key[E]{value: sample.Value, key: math.Pow(uniform(rand), 1/weight)})
+			if len(keys) == k {
+				jump = math.Log(uniform(rand)) / math.Log(keys[0].key)
+			}
+			return
+		}
+		if jump -= weight; jump > 0 {
+			return
+		}
+		var (
+			threshold = math.Pow(keys[0].key, weight)
+			r         = threshold + (1-threshold)*rand.Float64()
+		)
+		keys[0] = key[E]{value: sample.Value, key: math.Pow(r, 1/weight)}
+		heap.Fix(&keys, 0)
+		jump = math.Log(uniform(rand)) / math.Log(keys[0].key)
+	})
+	for _, k := range keys {
+		reservoir = append(reservoir, k.value)
+	}
+	return
+}
+
+// uniform draws a number in range (0, 1), so its logarithm is finite
+func uniform(rand FloatRand) (u float64) {
+	for u == 0 {
+		u = rand.Float64()
+	}
+	return
+}
+
+type key[E any] struct {
+	value E
+	key   float64
+}
+
+// keyed is a min-heap of elements by their keys
+type keyed[E any] []key[E]
+
+func (h keyed[E]) Len() int           { return len(h) }
+func (h keyed[E]) Less(i, j int) bool { return h[i].key < h[j].key }
+func (h keyed[E]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *keyed[E]) Push(x any)        { *h = append(*h, x.(key[E])) }
+func (h *keyed[E]) Pop() any {
+	var (
+		old = *h
+		x   = old[len(old)-1]
+	)
+	*h = old[:len(old)-1]
+	return x
+}
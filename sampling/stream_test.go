@@ -0,0 +1,124 @@
+package sampling
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/publiczny81/ml/utils"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+// numbers returns stream of n consecutive integers
+func numbers(n int) Stream[int] {
+	var i int
+	return NewGeneratorStream(func(context.Context) (int, error) {
+		if i == n {
+			return 0, io.EOF
+		}
+		i++
+		return i - 1, nil
+	})
+}
+
+func TestChannelStream(t *testing.T) {
+	var ch = make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	close(ch)
+	var actual []Sample[int]
+	for sample := range NewChannelStream(ch).Samples(context.TODO()) {
+		actual = append(actual, sample)
+	}
+	assert.Equal(t, []Sample[int]{ValueOf(1), ValueOf(2)}, actual)
+}
+
+func TestGeneratorStreamWithError(t *testing.T) {
+	var (
+		err       = errors.New("error")
+		stream    = NewGeneratorStream(func(context.Context) (int, error) { return 0, err })
+		_, actual = ReservoirR[int](context.TODO(), stream, 1, utils.NewPCG(1))
+	)
+	assert.ErrorIs(t, actual, err)
+}
+
+func TestReservoirIsUniform(t *testing.T) {
+	var tests = []struct {
+		Name      string
+		Reservoir func(rand FloatRand) ([]int, error)
+	}{
+		{
+			Name: "ReservoirR",
+			Reservoir: func(rand FloatRand) ([]int, error) {
+				return ReservoirR(context.TODO(), numbers(50), 5, rand)
+			},
+		},
+		{
+			Name: "ReservoirL",
+			Reservoir: func(rand FloatRand) ([]int, error) {
+				return ReservoirL(context.TODO(), numbers(50), 5, rand)
+			},
+		},
+		{
+			Name: "WeightedReservoir",
+			Reservoir: func(rand FloatRand) ([]int, error) {
+				return WeightedReservoir(context.TODO(), numbers(50), 5, rand)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var (
+				rand   = utils.NewPCG(1)
+				counts = make([]int, 50)
+				trials = 4000
+			)
+			for range trials {
+				var reservoir, err = test.Reservoir(rand)
+				assert.NoError(t, err)
+				assert.Len(t, reservoir, 5)
+				for _, e := range reservoir {
+					counts[e]++
+				}
+			}
+			for e, count := range counts {
+				// each element is kept in 10% of trials
+				assert.InDelta(t, 0.1, float64(count)/float64(trials), 0.025, "element %d", e)
+			}
+		})
+	}
+}
+
+func TestWeightedReservoir(t *testing.T) {
+	var (
+		rand      = utils.NewPCG(1)
+		source, _ = NewWeightedSource[int](NewSliceSource([]int{0, 1, 2, 3}), []float64{1, 1, 1, 5})
+		counts    = make([]int, 4)
+		trials    = 4000
+	)
+	for range trials {
+		var reservoir, err = WeightedReservoir[int](context.TODO(), New[int](source, new(SystematicalStrategy[int])), 1, rand)
+		assert.NoError(t, err)
+		counts[reservoir[0]]++
+	}
+	assert.InDelta(t, 5.0/8, float64(counts[3])/float64(trials), 0.03)
+	assert.InDelta(t, 1.0/8, float64(counts[0])/float64(trials), 0.03)
+}
+
+func TestWeightedReservoirSkipsZeroWeights(t *testing.T) {
+	var (
+		source, _      = NewWeightedSource[int](NewSliceSource([]int{0, 1, 2}), []float64{0, 1, 0})
+		reservoir, err = WeightedReservoir[int](context.TODO(), New[int](source, new(SystematicalStrategy[int])), 2, utils.NewPCG(1))
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, reservoir)
+}
+
+func TestReservoirOfShortStream(t *testing.T) {
+	var reservoir, err = ReservoirL(context.TODO(), numbers(3), 5, utils.NewPCG(1))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, reservoir)
+
+	_, err = ReservoirR(context.TODO(), numbers(3), 0, utils.NewPCG(1))
+	assert.Error(t, err)
+}
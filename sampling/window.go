@@ -0,0 +1,179 @@
+package sampling
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"slices"
+	"strconv"
+)
+
+// WindowSource turns a sequential source of time steps into examples for forecasting. Features of an example
+// are feature columns of window consecutive steps followed by lag features, and its target are target columns
+// of horizon steps following the window. Windows are taken only within the source, so windowing each set
+// returned by SplitSet never mixes steps of different sets, see SplitWindows
+type WindowSource struct {
+	source   Source[[]float64]
+	window   int
+	stride   int
+	horizon  int
+	lags     []int
+	features []int
+	targets  []int
+}
+
+type WindowOption func(*WindowSource)
+
+// WithStride sets the number of steps between starts of consecutive windows. The default is 1
+func WithStride(stride int) WindowOption {
+	return func(s *WindowSource) {
+		s.stride = max(1, stride)
+	}
+}
+
+// WithHorizon sets the number of steps following the window which are predicted. The default is 1
+func WithHorizon(horizon int) WindowOption {
+	return func(s *WindowSource) {
+		s.horizon = max(1, horizon)
+	}
+}
+
+// WithLags appends to features target columns of steps lagging given number of steps behind the first predicted
+// step, e.g. 24 for a daily season of hourly data. Lags may be longer than the window, then first windows
+// are skipped until lagging steps are available
+func WithLags(lags ...int) WindowOption {
+	return func(s *WindowSource) {
+		s.lags = lags
+	}
+}
+
+// WithFeatureColumns selects columns of steps used as features. By default all columns are features
+func WithFeatureColumns(columns ...int) WindowOption {
+	return func(s *WindowSource) {
+		s.features = columns
+	}
+}
+
+// WithTargetColumns selects columns of steps which are predicted. By default all columns are predicted
+func WithTargetColumns(columns ...int) WindowOption {
+	return func(s *WindowSource) {
+		s.targets = columns
+	}
+}
+
+// NewWindowSource creates source of windows of given length
+func NewWindowSource(source Source[[]float64], window int, opts ...WindowOption) (s *WindowSource, err error) {
+	if window < 1 {
+		err = errors.WithMessagef(errors.InvalidParameterValueError, "NewWindowSource: window=%d", window)
+		return
+	}
+	s = &WindowSource{
+		source:  source,
+		window:  window,
+		stride:  1,
+		horizon: 1,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	for _, lag := range s.lags {
+		if lag < 1 {
+			return nil, errors.WithMessagef(errors.InvalidParameterValueError, "NewWindowSource: lag=%d", lag)
+		}
+	}
+	return
+}
+
+// SplitWindows splits the source with SplitSet and windows each set separately, so windows of different sets
+// never share steps
+func SplitWindows(source Source[[]float64], window int, ratio []float64, opts ...WindowOption) (s []Source[Example], err error) {
+	var sets []Source[[]float64]
+	if sets, err = SplitSet(source, ratio...); err != nil {
+		return
+	}
+	for _, set := range sets {
+		var windows *WindowSource
+		if windows, err = NewWindowSource(set, window, opts...); err != nil {
+			return nil, err
+		}
+		s = append(s, windows)
+	}
+	return
+}
+
+// start returns the first step of the first window, so that all lagging steps are within the source
+func (s *WindowSource) start() int {
+	var lag = 0
+	if len(s.lags) > 0 {
+		lag = slices.Max(s.lags)
+	}
+	return max(0, lag-s.window)
+}
+
+func (s *WindowSource) Count(ctx context.Context) (int, error) {
+	var count, err = s.source.Count(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var steps = count - s.start() - s.window - s.horizon
+	if steps < 0 {
+		return 0, nil
+	}
+	return steps/s.stride + 1, nil
+}
+
+func (s *WindowSource) Select(ctx context.Context, idx int) (e Example, err error) {
+	var count int
+	if count, err = s.Count(ctx); err != nil || idx < 0 || idx >= count {
+		return
+	}
+	var (
+		first  = s.start() + idx*s.stride
+		origin = first + s.window
+		step   []float64
+	)
+	for t := first; t < origin; t++ {
+		if step, err = s.step(ctx, t); err != nil {
+			return
+		}
+		e.Features = append(e.Features, columns(step, s.features)...)
+	}
+	for _, lag := range s.lags {
+		if step, err = s.step(ctx, origin-lag); err != nil {
+			return
+		}
+		e.Features = append(e.Features, columns(step, s.targets)...)
+	}
+	for t := origin; t < origin+s.horizon; t++ {
+		if step, err = s.step(ctx, t); err != nil {
+			return
+		}
+		e.Target = append(e.Target, columns(step, s.targets)...)
+	}
+	e.ID = strconv.Itoa(first)
+	return
+}
+
+// step selects the step of the source and checks whether selected columns are within it
+func (s *WindowSource) step(ctx context.Context, t int) (step []float64, err error) {
+	if step, err = s.source.Select(ctx, t); err != nil {
+		return
+	}
+	for _, column := range append(slices.Clone(s.features), s.targets...) {
+		if column < 0 || column >= len(step) {
+			return nil, errors.WithMessagef(errors.InvalidParameterValueError, "WindowSource: step=%d, column=%d, len(step)=%d", t, column, len(step))
+		}
+	}
+	return
+}
+
+// columns returns values of given columns of the step or all values when columns are not given
+func columns(step []float64, columns []int) []float64 {
+	if columns == nil {
+		return step
+	}
+	var values = make([]float64, len(columns))
+	for i, column := range columns {
+		values[i] = step[column]
+	}
+	return values
+}
@@ -0,0 +1,78 @@
+package sampling
+
+import (
+	"context"
+	"github.com/publiczny81/ml/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// series returns steps of two columns where the first one is the step and the second one is its tenfold
+func series(n int) *SliceSource[[][]float64, []float64] {
+	var steps = make([][]float64, n)
+	for i := range steps {
+		steps[i] = []float64{float64(i), float64(10 * i)}
+	}
+	return NewSliceSource(steps)
+}
+
+func TestWindowSource(t *testing.T) {
+	var source, err = NewWindowSource(series(10), 3, WithStride(2), WithHorizon(2), WithFeatureColumns(1), WithTargetColumns(0))
+	assert.NoError(t, err)
+	assert.Equal(t, []Example{
+		{Features: []float64{0, 10, 20}, Target: []float64{3, 4}, ID: "0"},
+		{Features: []float64{20, 30, 40}, Target: []float64{5, 6}, ID: "2"},
+		{Features: []float64{40, 50, 60}, Target: []float64{7, 8}, ID: "4"},
+	}, values(t, []Source[Example]{source})[0])
+}
+
+func TestWindowSourceWithLags(t *testing.T) {
+	var source, err = NewWindowSource(series(8), 2, WithLags(5), WithTargetColumns(0))
+	assert.NoError(t, err)
+	assert.Equal(t, []Example{
+		{Features: []float64{3, 30, 4, 40, 0}, Target: []float64{5}, ID: "3"},
+		{Features: []float64{4, 40, 5, 50, 1}, Target: []float64{6}, ID: "4"},
+		{Features: []float64{5, 50, 6, 60, 2}, Target: []float64{7}, ID: "5"},
+	}, values(t, []Source[Example]{source})[0])
+}
+
+func TestWindowSourceErrors(t *testing.T) {
+	var _, err = NewWindowSource(series(3), 0)
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+
+	_, err = NewWindowSource(series(3), 1, WithLags(0))
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+
+	var source *WindowSource
+	source, err = NewWindowSource(series(3), 1, WithTargetColumns(2))
+	assert.NoError(t, err)
+	_, err = source.Select(context.TODO(), 0)
+	assert.ErrorIs(t, err, errors.InvalidParameterValueError)
+
+	var count int
+	count, err = source.Count(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	source, _ = NewWindowSource(series(3), 3)
+	count, err = source.Count(context.TODO())
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestSplitWindows(t *testing.T) {
+	var sets, err = SplitWindows(series(20), 3, []float64{0.6, 0.2, 0.2}, WithTargetColumns(0))
+	assert.NoError(t, err)
+
+	var last = -1.0
+	for i, set := range values(t, sets) {
+		assert.Len(t, set, []int{9, 1, 1}[i])
+		for _, e := range set {
+			// steps of each window follow steps of all windows of preceding sets
+			assert.Greater(t, e.Features[0], last)
+		}
+		if len(set) > 0 {
+			last = set[len(set)-1].Target[0]
+		}
+	}
+}
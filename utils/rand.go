@@ -1,13 +1,29 @@
 package utils
 
 import (
+	"encoding"
+	"encoding/binary"
 	"math/rand/v2"
 )
 
+// Rand wraps the global source of math/rand/v2 and implements ports.Rand. Runs using it cannot be reproduced,
+// use NewPCG or NewChaCha8 instead
 var Rand = new(randomizer)
 
 type randomizer struct{}
 
+func (r *randomizer) Int() int {
+	return rand.Int()
+}
+
+func (r *randomizer) IntN(n int) int {
+	return rand.IntN(n)
+}
+
+func (r *randomizer) Uint64() uint64 {
+	return rand.Uint64()
+}
+
 func (r *randomizer) Float64() float64 {
 	return rand.Float64()
 }
@@ -15,3 +31,75 @@ func (r *randomizer) Float64() float64 {
 func (r *randomizer) NormFloat64() float64 {
 	return rand.NormFloat64()
 }
+
+// source is a source of random numbers whose state can be saved and restored
+type source interface {
+	rand.Source
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// Random is a seeded random number generator implementing ports.Rand. Its state can be saved with MarshalBinary.
+// It is not safe for concurrent use, so each worker goroutine should use its own child stream created with Split
+type Random struct {
+	*rand.Rand
+	source source
+	split  func(parent *Random) source
+}
+
+// NewPCG creates generator seeded with given seed using PCG source
+func NewPCG(seed uint64) *Random {
+	return newRandom(newPCG(seed, splitMix(seed)), func(parent *Random) source {
+		return newPCG(parent.Uint64(), parent.Uint64())
+	})
+}
+
+// NewChaCha8 creates generator seeded with given seed using ChaCha8 source
+func NewChaCha8(seed uint64) *Random {
+	return newRandom(newChaCha8(seed), func(parent *Random) source {
+		return newChaCha8(parent.Uint64())
+	})
+}
+
+func newRandom(s source, split func(*Random) source) *Random {
+	return &Random{
+		Rand:   rand.New(s),
+		source: s,
+		split:  split,
+	}
+}
+
+func newPCG(seed1, seed2 uint64) source {
+	return rand.NewPCG(seed1, seed2)
+}
+
+func newChaCha8(seed uint64) source {
+	var key [32]byte
+	for i := range 4 {
+		seed = splitMix(seed)
+		binary.LittleEndian.PutUint64(key[i*8:], seed)
+	}
+	return rand.NewChaCha8(key)
+}
+
+// Split creates independent child stream of the same kind. Children created in the same order from generators
+// seeded in the same way produce the same numbers
+func (r *Random) Split() *Random {
+	return newRandom(r.split(r), r.split)
+}
+
+func (r *Random) MarshalBinary() ([]byte, error) {
+	return r.source.MarshalBinary()
+}
+
+func (r *Random) UnmarshalBinary(data []byte) error {
+	return r.source.UnmarshalBinary(data)
+}
+
+// splitMix scrambles the seed with SplitMix64 finalizer
+func splitMix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
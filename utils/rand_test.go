@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"github.com/publiczny81/ml/ports"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var (
+	_ types.Rand = NewPCG(0)
+	_ types.Rand = Rand
+)
+
+func TestRandom(t *testing.T) {
+	var tests = []struct {
+		Name string
+		New  func(seed uint64) *Random
+	}{
+		{Name: "PCG", New: NewPCG},
+		{Name: "ChaCha8", New: NewChaCha8},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var (
+				first  = test.New(42)
+				second = test.New(42)
+				other  = test.New(43)
+			)
+			var expected = first.Uint64()
+			assert.Equal(t, expected, second.Uint64())
+			assert.NotEqual(t, expected, other.Uint64())
+
+			var (
+				firstChild  = first.Split()
+				secondChild = second.Split()
+			)
+			assert.Equal(t, firstChild.NormFloat64(), secondChild.NormFloat64())
+			assert.Equal(t, first.Uint64(), second.Uint64())
+			assert.NotEqual(t, firstChild.Uint64(), first.Uint64())
+
+			state, err := first.MarshalBinary()
+			assert.NoError(t, err)
+			expected = first.Uint64()
+
+			var restored = test.New(0)
+			assert.NoError(t, restored.UnmarshalBinary(state))
+			assert.Equal(t, expected, restored.Uint64())
+		})
+	}
+}